@@ -0,0 +1,507 @@
+// Package assign sorts students into the classes of a session using their interest preferences.
+//
+// The Greedy mode is a port of the sortinghat script: students are sorted with the pickiest first,
+// then each student is placed in an available class for one of their top picks. The Optimal mode
+// solves the whole session at once as a min-cost flow problem. In both modes anyone who cannot be
+// placed goes into a catch-all Fallback class.
+package assign
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// FallbackName is the name of the catch-all class for students who could not be placed.
+// The Fallback class has an empty class ID.
+const FallbackName = "Fallback"
+
+// Mode selects the assignment algorithm
+type Mode string
+
+const (
+	// Greedy places the pickiest students first, one at a time, like the sortinghat script
+	Greedy Mode = "greedy"
+
+	// Optimal places every student at once, maximizing the total interest score. A student only goes
+	// to the Fallback class when there is no way to fit everyone into a class they are eligible for.
+	Optimal Mode = "optimal"
+)
+
+// ParseMode returns the mode with the given name
+func ParseMode(name string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(name))) {
+	case Greedy, "":
+		return Greedy, nil
+	case Optimal:
+		return Optimal, nil
+	}
+	return "", fmt.Errorf("unknown assignment mode %q, expected greedy or optimal", name)
+}
+
+// Input is everything needed to assign the students of one session
+type Input struct {
+	Session     int
+	Classes     []model.Class // classes in other sessions are ignored
+	Students    []model.Student
+	Preferences []model.StudentPreference
+	Manual      []model.ClassStudent // students placed in a class by the organizer
+	Exclusions  []model.ClassStudent // classes a student must not be placed in
+	Skips       []model.Skip         // students left out of the assignment
+	Cancel      []string             // IDs of classes cancelled for the session
+
+	// Adults and AdultAssignments link class leaders to their children through their household, so
+	// a leader's children are placed in the leader's class
+	Adults           []model.Adult
+	AdultAssignments []model.AdultAssignment
+
+	// History is the final assignments of earlier sessions in the term. Students who got a class they were
+	// less interested in last session are placed first, and nobody repeats a class or an interest area.
+	History []model.Assignment
+
+	// Mode is the assignment algorithm, Greedy when empty
+	Mode Mode
+
+	// Seed seeds the shuffles so a run can be repeated exactly, a random seed is used when 0.
+	// The seed used is returned in the Result.
+	Seed uint64
+
+	// Weights turns on a local search after the placement that rearranges students to raise the
+	// weighted score, including stream balance, grade spread, pair requests and disappointment
+	Weights      *Weights
+	Pairs        []model.StudentPair // students asked to be together or apart
+	Disappointed []string            // students who got a class they were not interested in last session
+}
+
+// Result is the outcome of an assignment run
+type Result struct {
+	Assignments []model.Assignment
+	Warnings    []string
+	Seed        uint64
+	UnmetPairs  []model.StudentPair // pair requests the assignment does not meet
+
+	// Cancellations suggests cancelling the classes below their minimum enrollment
+	Cancellations []Cancellation
+}
+
+type student struct {
+	model.Student
+	name        string
+	preferences []model.AreaInterest
+	course      *course
+	deficit     int // how far the student's class last session fell short of a very interested one
+}
+
+// interestIn returns the student's interest in the course area
+func (s *student) interestIn(c *course) model.Interest {
+	for _, pref := range s.preferences {
+		if pref.Area == c.InterestArea {
+			return pref.Level
+		}
+	}
+	return model.NotInterested
+}
+
+// preferenceCounts counts the very interested, interested and not interested areas
+func (s *student) preferenceCounts() [3]int {
+	var counts [3]int
+	for _, pref := range s.preferences {
+		counts[levelIndex(pref.Level)]++
+	}
+	return counts
+}
+
+// orderedPreferences lists the areas from most to least interested, keeping the shuffled order within a level
+func (s *student) orderedPreferences() []model.AreaInterest {
+	ordered := slices.Clone(s.preferences)
+	slices.SortStableFunc(ordered, func(a, b model.AreaInterest) int {
+		return levelIndex(a.Level) - levelIndex(b.Level)
+	})
+	return ordered
+}
+
+type course struct {
+	model.Class
+	capacity int // remaining places
+	students []*student
+}
+
+func (c *course) availableTo(s *student) bool {
+	return c.capacity > 0 && c.AcceptsGrade(s.Grade)
+}
+
+func (c *course) assign(s *student) {
+	s.course = c
+	c.students = append(c.students, s)
+	c.capacity--
+}
+
+// Assign places every student of the session in a class, honoring class capacity, grade range,
+// manual assignments and exclusions. Manual assignments are placed first and may go over capacity
+// or outside the grade range.
+func Assign(input Input) (Result, error) {
+	var result Result
+
+	result.Seed = input.Seed
+	for result.Seed == 0 {
+		result.Seed = newSeed()
+	}
+	r := newRand(result.Seed)
+
+	skip := make(map[string]bool)
+	for _, s := range input.Skips {
+		skip[nameKey(s.FullName)] = true
+	}
+
+	// Index the preferences and collect the known interest areas in column order
+	preferences := make(map[string]model.StudentPreference)
+	var knownAreas []string
+	for _, pref := range input.Preferences {
+		for _, area := range pref.Areas() {
+			if !slices.Contains(knownAreas, area) {
+				knownAreas = append(knownAreas, area)
+			}
+		}
+		if skip[nameKey(pref.FullName)] {
+			continue
+		}
+		preferences[nameKey(pref.FullName)] = pref
+	}
+
+	// Students without a preference form are assumed to be very interested in everything, which sorts
+	// them to the end of the list
+	defaultPreferences := make([]model.AreaInterest, 0, len(knownAreas))
+	for _, area := range knownAreas {
+		defaultPreferences = append(defaultPreferences, model.AreaInterest{Area: area, Level: model.VeryInterested})
+	}
+
+	var students []*student
+	studentsByName := make(map[string]*student)
+	for _, s := range input.Students {
+		key := nameKey(s.FullName())
+		if skip[key] {
+			continue
+		}
+
+		st := &student{Student: s, name: s.FullName()}
+		if pref, exists := preferences[key]; exists {
+			st.preferences = slices.Clone(pref.Interests)
+			delete(preferences, key)
+		} else {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("No preferences for %s", st.name))
+			st.preferences = slices.Clone(defaultPreferences)
+		}
+
+		// Shuffle the preferences so that if a student has multiple options we don't always
+		// pick them in the same order
+		r.Shuffle(len(st.preferences), func(i, j int) {
+			st.preferences[i], st.preferences[j] = st.preferences[j], st.preferences[i]
+		})
+
+		students = append(students, st)
+		studentsByName[key] = st
+	}
+
+	// Every preference must belong to a student, otherwise a name needs cleaning up
+	var unmatched []string
+	for _, pref := range input.Preferences {
+		if _, exists := preferences[nameKey(pref.FullName)]; exists {
+			unmatched = append(unmatched, pref.FullName)
+		}
+	}
+	if len(unmatched) > 0 {
+		return result, fmt.Errorf("preferences without a student, clean the names or add them to the skip list: %s", strings.Join(unmatched, ", "))
+	}
+
+	// Pair requests for unknown students can never be met, but they shouldn't stop the assignment
+	for _, pair := range input.Pairs {
+		for _, name := range []string{pair.StudentA, pair.StudentB} {
+			if _, exists := studentsByName[nameKey(name)]; !exists && !skip[nameKey(name)] {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Pair request for unknown student %s", name))
+			}
+		}
+	}
+
+	// Set up the courses of the session, starting with the catch-all Fallback class
+	fallback := &course{
+		Class:    model.Class{Name: FallbackName, Session: input.Session, InterestArea: "none", GradeMin: 0, GradeMax: 999, StudentCapacity: 999},
+		capacity: 999,
+	}
+	courses := []*course{fallback}
+	coursesByID := map[string]*course{"": fallback}
+	coursesByArea := make(map[string][]*course)
+	// Cancelled classes are left out, but manual assignments and exclusions may still name them
+	cancelled := make(map[string]bool)
+	for _, id := range input.Cancel {
+		if !slices.ContainsFunc(input.Classes, func(class model.Class) bool { return class.ID == id && class.Session == input.Session }) {
+			return result, fmt.Errorf("could not cancel class %s, it is not in session %d", id, input.Session)
+		}
+		cancelled[id] = true
+	}
+	for _, class := range input.Classes {
+		if class.Session != input.Session || cancelled[class.ID] {
+			continue
+		}
+		if len(knownAreas) > 0 && !slices.Contains(knownAreas, class.InterestArea) {
+			return result, fmt.Errorf("unexpected interest area (%s) for class %s", class.InterestArea, class.Name)
+		}
+
+		c := &course{Class: class, capacity: class.StudentCapacity}
+		courses = append(courses, c)
+		coursesByID[class.ID] = c
+		coursesByArea[class.InterestArea] = append(coursesByArea[class.InterestArea], c)
+	}
+
+	// First assign any manual assignments
+	locked := make(map[*student]bool)
+	for _, manual := range input.Manual {
+		c, exists := coursesByID[manual.ClassID]
+		if cancelled[manual.ClassID] {
+			return result, fmt.Errorf("could not manually assign %s to class %s, it is cancelled", manual.StudentFullName, manual.ClassID)
+		}
+		if !exists {
+			return result, fmt.Errorf("could not manually assign class %s, it is not in session %d", manual.ClassID, input.Session)
+		}
+		s, exists := studentsByName[nameKey(manual.StudentFullName)]
+		if !exists {
+			return result, fmt.Errorf("could not manually assign student %s, they are not in the student list", manual.StudentFullName)
+		}
+		if s.course != nil {
+			return result, fmt.Errorf("student %s is manually assigned more than once", manual.StudentFullName)
+		}
+		c.assign(s)
+		locked[s] = true
+	}
+
+	// Record any exclusions: classes we shouldn't put students into. This helps ensure people
+	// aren't in the same class multiple times if it's too similar
+	exclusions := make(map[string][]*course)
+	for _, exclusion := range input.Exclusions {
+		c, exists := coursesByID[exclusion.ClassID]
+		if cancelled[exclusion.ClassID] {
+			continue
+		}
+		if !exists {
+			return result, fmt.Errorf("could not manually exclude class %s, it is not in session %d", exclusion.ClassID, input.Session)
+		}
+		key := nameKey(exclusion.StudentFullName)
+		exclusions[key] = append(exclusions[key], c)
+	}
+
+	// Keep students out of the classes and interest areas they had earlier in the term, and note how
+	// interested they were in their last class
+	history := earlierSessions(input.History, input.Session)
+	if len(history) > 0 {
+		unknownAreas := make(map[string]bool)
+		latest := latestAssignments(history)
+		for _, past := range history {
+			key := nameKey(past.StudentFullName)
+			if past.ClassID == "" {
+				continue
+			}
+			area, found := pastInterestArea(input.Classes, past)
+			if !found && !unknownAreas[past.ClassName] {
+				unknownAreas[past.ClassName] = true
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Could not find the interest area of %s from session %d in the class catalog, only the class itself is excluded",
+					past.ClassName, past.ClassSession))
+			}
+			for _, c := range courses[1:] {
+				sameClass := strings.EqualFold(strings.TrimSpace(c.Name), strings.TrimSpace(past.ClassName))
+				sameArea := area != "" && c.InterestArea == area
+				if (sameClass || sameArea) && !slices.Contains(exclusions[key], c) {
+					exclusions[key] = append(exclusions[key], c)
+				}
+			}
+		}
+		for _, s := range students {
+			if past, exists := latest[nameKey(s.name)]; exists {
+				s.deficit = priorDeficit(past)
+			}
+		}
+	}
+
+	// Place the children of class leaders in their parent's class, unless they must be kept apart from
+	// someone already there
+	rules := newPairRules(input.Pairs, studentsByName)
+	result.Warnings = append(result.Warnings, placeLeaderChildren(input, studentsByName, coursesByID, exclusions, locked, rules)...)
+
+	// Place the students who must share a class first, while there is room for the whole group
+	result.Warnings = append(result.Warnings, rules.placeGroups(courses, exclusions, locked, r)...)
+
+	// Sort the students by the most disappointed last session, then the pickiest (fewest very interested areas)
+	slices.SortStableFunc(students, func(a, b *student) int {
+		if a.deficit != b.deficit {
+			return b.deficit - a.deficit
+		}
+		ca, cb := a.preferenceCounts(), b.preferenceCounts()
+		for i := range ca {
+			if ca[i] != cb[i] {
+				return ca[i] - cb[i]
+			}
+		}
+		return 0
+	})
+
+	switch input.Mode {
+	case Optimal:
+		assignOptimal(students, courses[1:], exclusions, r)
+	case Greedy, "":
+		// Assign each student by iterating over their preferences and checking which classes they could be
+		// assigned to. Students who were manually assigned are skipped.
+		for _, s := range students {
+			if s.course != nil {
+				continue
+			}
+			assignStudent(s, coursesByArea, exclusions[nameKey(s.name)], rules, r)
+		}
+	default:
+		return result, fmt.Errorf("unknown assignment mode %q", input.Mode)
+	}
+
+	// The min-cost flow knows nothing of students who must be kept apart, so move one of each such pair
+	result.Warnings = append(result.Warnings, rules.separate(input.Pairs, studentsByName, courses, exclusions, locked)...)
+
+	for _, s := range students {
+		if s.course == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("No available class for %s (%d)", s.name, s.Grade))
+			fallback.assign(s)
+		}
+	}
+
+	// Soft pair requests are only met by the weighted search, so it runs with the default weights when
+	// there are soft requests and no weights were given
+	weights := input.Weights
+	if weights == nil && hasSoft(input.Pairs) {
+		weights = &DefaultWeights
+	}
+	if weights != nil {
+		scorer := Scorer{Weights: *weights, Pairs: input.Pairs, Disappointed: input.Disappointed}
+		improve(scorer, input.Session, courses, students, locked, exclusions, rules)
+	}
+
+	result.Assignments = courseAssignments(courses, input.Session)
+	result.UnmetPairs = Scorer{Pairs: input.Pairs}.Score(result.Assignments).UnmetPairs
+	cancellations, warnings := suggestCancellations(input.Session, courses, exclusions, locked, rules)
+	result.Cancellations = cancellations
+	result.Warnings = append(result.Warnings, warnings...)
+	return result, nil
+}
+
+// newSeed picks a random seed for a run that was not given one
+func newSeed() uint64 {
+	return rand.Uint64()
+}
+
+// newRand returns the source of the shuffles for a seed. The same seed always gives the same shuffles.
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// assignStudent places the student in the first available class for their most preferred area
+func assignStudent(s *student, coursesByArea map[string][]*course, excluded []*course, rules pairRules, r *rand.Rand) bool {
+	for _, pref := range s.orderedPreferences() {
+		candidates := coursesByArea[pref.Area]
+
+		// Shuffle the courses each time so we don't always assign people to the first course listed
+		r.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+
+		for _, c := range candidates {
+			if c.availableTo(s) && !slices.Contains(excluded, c) && !rules.conflicts(s, c) {
+				c.assign(s)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assignOptimal places all unassigned students at once by solving a min-cost flow from students to classes.
+// Placing a student in the Fallback class costs more than any rearrangement of the other students, so the
+// fewest possible students end up there, and among those placements the total interest score is the highest.
+func assignOptimal(students []*student, courses []*course, exclusions map[string][]*course, r *rand.Rand) {
+	var pending []*student
+	for _, s := range students {
+		if s.course == nil {
+			pending = append(pending, s)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	// Shuffle so that ties between equally good placements are broken differently on each run
+	r.Shuffle(len(pending), func(i, j int) {
+		pending[i], pending[j] = pending[j], pending[i]
+	})
+
+	// Nodes: source, one per student, one per course, sink
+	source := 0
+	studentNode := func(i int) int { return 1 + i }
+	courseNode := func(i int) int { return 1 + len(pending) + i }
+	sink := 1 + len(pending) + len(courses)
+	g := newFlowGraph(sink + 1)
+
+	// Costs are the score lost compared to a very interested placement, multiplied for students who were
+	// disappointed last session so their placements count for more
+	unplacedCost := maxInterestScore*(1+maxInterestScore)*len(pending) + 1
+
+	type placement struct {
+		edge    int
+		student *student
+		course  *course
+	}
+	var placements []placement
+	for i, s := range pending {
+		g.addEdge(source, studentNode(i), 1, 0)
+		for j, c := range courses {
+			if !c.AcceptsGrade(s.Grade) || slices.Contains(exclusions[nameKey(s.name)], c) {
+				continue
+			}
+			edge := g.addEdge(studentNode(i), courseNode(j), 1, (maxInterestScore-interestScore(s.interestIn(c)))*(1+s.deficit))
+			placements = append(placements, placement{edge: edge, student: s, course: c})
+		}
+		g.addEdge(studentNode(i), sink, 1, unplacedCost)
+	}
+	for j, c := range courses {
+		if c.capacity > 0 {
+			g.addEdge(courseNode(j), sink, c.capacity, 0)
+		}
+	}
+
+	g.minCostFlow(source, sink)
+
+	for _, p := range placements {
+		if g.edges[p.edge].flow > 0 {
+			p.course.assign(p.student)
+		}
+	}
+}
+
+// maxInterestScore is the score of a very interested placement
+const maxInterestScore = 2
+
+// interestScore rates a placement by the student's interest in the class area
+func interestScore(level model.Interest) int {
+	return maxInterestScore - levelIndex(level)
+}
+
+// levelIndex orders interest levels from most to least interested
+func levelIndex(level model.Interest) int {
+	switch level {
+	case model.VeryInterested:
+		return 0
+	case model.Interested:
+		return 1
+	}
+	return 2
+}
+
+// nameKey normalizes a full name for matching across files
+func nameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,332 @@
+package assign
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// testClass returns a class of session 1
+func testClass(id, area string, gradeMin, gradeMax, capacity int) model.Class {
+	return model.Class{ID: id, Session: 1, Name: "Class " + id, InterestArea: area, GradeMin: gradeMin, GradeMax: gradeMax, StudentCapacity: capacity}
+}
+
+// addStudent adds a student and their preferences to the input. The levels are the student's
+// interest in the areas, in order.
+func addStudent(input *Input, name string, grade int, stream string, areas []string, levels ...model.Interest) {
+	first, last, _ := strings.Cut(name, " ")
+	input.Students = append(input.Students, model.Student{FirstName: first, LastName: last, Grade: grade, Stream: stream})
+	pref := model.StudentPreference{FullName: name}
+	for i, area := range areas {
+		pref.Interests = append(pref.Interests, model.AreaInterest{Area: area, Level: levels[i]})
+	}
+	input.Preferences = append(input.Preferences, pref)
+}
+
+// placements maps each student to the ID of their class
+func placements(assignments []model.Assignment) map[string]string {
+	placed := make(map[string]string)
+	for _, a := range assignments {
+		placed[a.StudentFullName] = a.ClassID
+	}
+	return placed
+}
+
+// checkLimits fails the test unless every student is placed exactly once, within the capacity and grade
+// range of their class
+func checkLimits(t *testing.T, input Input, result Result) {
+	t.Helper()
+	if len(result.Assignments) != len(input.Students) {
+		t.Errorf("got %d assignments for %d students", len(result.Assignments), len(input.Students))
+	}
+	classes := make(map[string]model.Class)
+	for _, class := range input.Classes {
+		classes[class.ID] = class
+	}
+	seen := make(map[string]bool)
+	enrolled := make(map[string]int)
+	for _, a := range result.Assignments {
+		if seen[a.StudentFullName] {
+			t.Errorf("%s is placed more than once", a.StudentFullName)
+		}
+		seen[a.StudentFullName] = true
+		if a.ClassID == "" {
+			continue
+		}
+		class := classes[a.ClassID]
+		enrolled[a.ClassID]++
+		if !class.AcceptsGrade(a.StudentGrade) {
+			t.Errorf("%s (%d) is in %s for grades %d to %d", a.StudentFullName, a.StudentGrade, a.ClassID, class.GradeMin, class.GradeMax)
+		}
+	}
+	for id, count := range enrolled {
+		if count > classes[id].StudentCapacity {
+			t.Errorf("class %s has %d students for %d places", id, count, classes[id].StudentCapacity)
+		}
+	}
+}
+
+// interestTotal is the sum of the interest scores of the assignments
+func interestTotal(assignments []model.Assignment) int {
+	total := 0
+	for _, a := range assignments {
+		total += assignmentInterestScore(a)
+	}
+	return total
+}
+
+func TestAssignOptimalRespectsLimits(t *testing.T) {
+	areas := []string{"art", "games", "music"}
+	input := Input{
+		Session: 1,
+		Mode:    Optimal,
+		Classes: []model.Class{
+			testClass("a1", "art", 1, 3, 3),
+			testClass("a2", "art", 4, 6, 2),
+			testClass("g1", "games", 1, 6, 3),
+			testClass("m1", "music", 2, 5, 2),
+		},
+	}
+	levels := []model.Interest{model.VeryInterested, model.Interested, model.NotInterested}
+	for i, name := range []string{"Ada A", "Ben B", "Cal C", "Dee D", "Eve E", "Fay F", "Gus G", "Hal H", "Ivy I", "Jo J"} {
+		addStudent(&input, name, 1+i%6, "green", areas, levels[i%3], levels[(i+1)%3], levels[(i+2)%3])
+	}
+
+	for seed := uint64(1); seed <= 20; seed++ {
+		input.Seed = seed
+		result, err := Assign(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkLimits(t, input, result)
+		for _, a := range result.Assignments {
+			if a.ClassID == "" {
+				t.Errorf("seed %d: %s is in the Fallback class though the classes have room", seed, a.StudentFullName)
+			}
+		}
+	}
+}
+
+func TestAssignOptimalBeatsGreedy(t *testing.T) {
+	// Ben has fewer very interested areas so greedy places him first, in the one art class. Ada is
+	// left with music, which Ada is not interested in, while Ben would have been just as happy there.
+	areas := []string{"art", "music"}
+	input := Input{
+		Session: 1,
+		Seed:    1,
+		Classes: []model.Class{testClass("art", "art", 1, 6, 1), testClass("music", "music", 1, 6, 1)},
+	}
+	addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.NotInterested)
+	addStudent(&input, "Ben B", 3, "green", areas, model.Interested, model.NotInterested)
+
+	input.Mode = Greedy
+	greedy, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.Mode = Optimal
+	optimal, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := placements(optimal.Assignments); got["Ada A"] != "art" || got["Ben B"] != "music" {
+		t.Errorf("optimal placed %v, want Ada in art and Ben in music", got)
+	}
+	if g, o := interestTotal(greedy.Assignments), interestTotal(optimal.Assignments); o != 2 || g >= o {
+		t.Errorf("got greedy interest %d and optimal %d, want optimal 2 and greedy lower", g, o)
+	}
+}
+
+func TestAssignOptimalFallback(t *testing.T) {
+	areas := []string{"art"}
+	input := Input{
+		Session: 1,
+		Seed:    1,
+		Mode:    Optimal,
+		Classes: []model.Class{testClass("art", "art", 1, 3, 1)},
+	}
+	addStudent(&input, "Ada A", 2, "green", areas, model.VeryInterested)
+	addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested)
+	addStudent(&input, "Cal C", 5, "green", areas, model.VeryInterested)
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLimits(t, input, result)
+
+	placed := placements(result.Assignments)
+	if placed["Cal C"] != "" {
+		t.Errorf("Cal (5) is in %q, want the Fallback class", placed["Cal C"])
+	}
+	inArt := 0
+	for _, name := range []string{"Ada A", "Ben B"} {
+		if placed[name] == "art" {
+			inArt++
+		}
+	}
+	if inArt != 1 {
+		t.Errorf("got %d of Ada and Ben in art, want 1 with the other in the Fallback class", inArt)
+	}
+
+	warned := 0
+	for _, warning := range result.Warnings {
+		if strings.HasPrefix(warning, "No available class") {
+			warned++
+		}
+	}
+	if warned != 2 {
+		t.Errorf("got warnings %q, want one for each student in the Fallback class", result.Warnings)
+	}
+}
+
+func TestAssignGreedyRespectsLimits(t *testing.T) {
+	areas := []string{"art", "games", "music"}
+	input := Input{
+		Session: 1,
+		Classes: []model.Class{
+			testClass("a1", "art", 1, 3, 2),
+			testClass("a2", "art", 4, 6, 2),
+			testClass("g1", "games", 1, 6, 3),
+			testClass("m1", "music", 2, 5, 2),
+			{ID: "x1", Session: 2, Name: "Other session", InterestArea: "art", GradeMin: 1, GradeMax: 6, StudentCapacity: 10},
+		},
+	}
+	for i, name := range []string{"Ada A", "Ben B", "Cal C", "Dee D", "Eve E", "Fay F", "Gus G", "Hal H", "Ivy I", "Jo J", "Kit K"} {
+		addStudent(&input, name, 1+i%6, "green", areas, model.VeryInterested, model.Interested, model.NotInterested)
+	}
+
+	for seed := uint64(1); seed <= 20; seed++ {
+		input.Seed = seed
+		result, err := Assign(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkLimits(t, input, result)
+		for _, a := range result.Assignments {
+			if a.ClassID == "x1" {
+				t.Errorf("seed %d: %s is in a class of another session", seed, a.StudentFullName)
+			}
+		}
+	}
+}
+
+func TestAssignGreedyFallback(t *testing.T) {
+	areas := []string{"art"}
+	input := Input{
+		Session: 1,
+		Seed:    1,
+		Classes: []model.Class{testClass("art", "art", 1, 3, 1)},
+	}
+	addStudent(&input, "Ada A", 2, "green", areas, model.VeryInterested)
+	addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested)
+	addStudent(&input, "Cal C", 5, "green", areas, model.VeryInterested)
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLimits(t, input, result)
+	placed := placements(result.Assignments)
+	if placed["Cal C"] != "" || (placed["Ada A"] == "art") == (placed["Ben B"] == "art") {
+		t.Errorf("got %v, want one of Ada and Ben in art and everyone else in the Fallback class", placed)
+	}
+	for _, a := range result.Assignments {
+		if a.ClassID == "" && a.ClassName != FallbackName {
+			t.Errorf("%s is in class %q without an ID, want %s", a.StudentFullName, a.ClassName, FallbackName)
+		}
+	}
+}
+
+func TestAssignManual(t *testing.T) {
+	areas := []string{"art", "games"}
+	input := Input{
+		Session: 1,
+		Seed:    1,
+		Classes: []model.Class{testClass("art", "art", 1, 3, 1), testClass("games", "games", 1, 6, 3)},
+		// Manual assignments may go over capacity and outside the grade range
+		Manual: []model.ClassStudent{
+			{ClassID: "art", StudentFullName: "Ada A"},
+			{ClassID: "art", StudentFullName: " cal  c "},
+			{ClassID: "games", StudentFullName: "Ben B"},
+		},
+	}
+	addStudent(&input, "Ada A", 2, "green", areas, model.NotInterested, model.VeryInterested)
+	addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested, model.NotInterested)
+	addStudent(&input, "Cal C", 5, "green", areas, model.NotInterested, model.VeryInterested)
+	addStudent(&input, "Dee D", 2, "green", areas, model.VeryInterested, model.Interested)
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Ada A": "art", "Ben B": "games", "Cal C": "art", "Dee D": "games"}
+	if got := placements(result.Assignments); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for name, manual := range map[string]model.ClassStudent{
+		"unknown class":   {ClassID: "cooking", StudentFullName: "Dee D"},
+		"unknown student": {ClassID: "art", StudentFullName: "Zed Z"},
+		"twice":           {ClassID: "games", StudentFullName: "Ada A"},
+	} {
+		bad := input
+		bad.Manual = append(slices.Clone(input.Manual), manual)
+		if _, err := Assign(bad); err == nil {
+			t.Errorf("%s: Assign succeeded, want an error", name)
+		}
+	}
+}
+
+func TestAssignExclusions(t *testing.T) {
+	areas := []string{"art", "games"}
+	for seed := uint64(1); seed <= 10; seed++ {
+		input := Input{
+			Session:    1,
+			Seed:       seed,
+			Classes:    []model.Class{testClass("a1", "art", 1, 6, 3), testClass("a2", "art", 1, 6, 3), testClass("games", "games", 1, 6, 3)},
+			Exclusions: []model.ClassStudent{{ClassID: "a1", StudentFullName: "Ada A"}, {ClassID: "a2", StudentFullName: "Ada A"}, {ClassID: "a1", StudentFullName: "Ben B"}},
+		}
+		addStudent(&input, "Ada A", 2, "green", areas, model.VeryInterested, model.Interested)
+		addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested, model.Interested)
+
+		result, err := Assign(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := placements(result.Assignments); got["Ada A"] != "games" || got["Ben B"] != "a2" {
+			t.Errorf("seed %d: got %v, want Ada in games and Ben in a2", seed, got)
+		}
+	}
+}
+
+func TestAssignSkips(t *testing.T) {
+	areas := []string{"art"}
+	input := Input{
+		Session: 1,
+		Seed:    1,
+		Classes: []model.Class{testClass("art", "art", 1, 6, 3)},
+		Skips:   []model.Skip{{FullName: "ben b", Reason: "moved away"}, {FullName: "Cal C"}},
+	}
+	addStudent(&input, "Ada A", 2, "green", areas, model.VeryInterested)
+	addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested)
+	// Cal has a preference form but is not in the student list, which is only allowed when skipped
+	input.Preferences = append(input.Preferences, model.StudentPreference{FullName: "Cal C"})
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"Ada A": "art"}; !reflect.DeepEqual(placements(result.Assignments), want) {
+		t.Errorf("got %v, want only Ada placed", placements(result.Assignments))
+	}
+
+	input.Skips = input.Skips[:1]
+	if _, err := Assign(input); err == nil || !strings.Contains(err.Error(), "Cal C") {
+		t.Errorf("Assign error = %v, want one naming the preferences without a student", err)
+	}
+}
@@ -0,0 +1,135 @@
+package assign
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Search is the outcome of a best-of-N assignment search
+type Search struct {
+	Best      Result    // the highest scoring run
+	BestScore Score     // the score of the best run
+	BestRun   int       // index of the best run, counting from 1
+	Seed      uint64    // seed the run seeds were drawn from, a search with the same seed repeats exactly
+	Scores    []float64 // total score of every run, in run order
+}
+
+// Runs is the number of runs in the search
+func (s Search) Runs() int {
+	return len(s.Scores)
+}
+
+// Sorted returns the run scores from lowest to highest
+func (s Search) Sorted() []float64 {
+	return slices.Sorted(slices.Values(s.Scores))
+}
+
+// Min is the lowest run score
+func (s Search) Min() float64 {
+	return slices.Min(s.Scores)
+}
+
+// Max is the highest run score, the score of the best run
+func (s Search) Max() float64 {
+	return slices.Max(s.Scores)
+}
+
+// Mean is the average run score
+func (s Search) Mean() float64 {
+	total := 0.0
+	for _, score := range s.Scores {
+		total += score
+	}
+	return total / float64(len(s.Scores))
+}
+
+// Median is the middle run score
+func (s Search) Median() float64 {
+	sorted := s.Sorted()
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// BeatPercent is the share of the other runs that scored lower than the best run
+func (s Search) BeatPercent() int {
+	if len(s.Scores) < 2 {
+		return 100
+	}
+	lower := 0
+	for _, score := range s.Scores {
+		if score < s.BestScore.Total {
+			lower++
+		}
+	}
+	return lower * 100 / (len(s.Scores) - 1)
+}
+
+// AssignBest runs the assignment the given number of times concurrently, each with a different seed,
+// scores every run and keeps the best one. Runs are scored with the input weights, or DefaultWeights when
+// none are set. The seed of each run is drawn from the input seed, so the best run can be repeated on
+// its own with its Result seed, and the whole search can be repeated with the Search seed.
+func AssignBest(input Input, runs int) (Search, error) {
+	search := Search{Seed: input.Seed}
+	for search.Seed == 0 {
+		search.Seed = newSeed()
+	}
+	runs = max(runs, 1)
+
+	r := newRand(search.Seed)
+	seeds := make([]uint64, runs)
+	for i := range seeds {
+		for seeds[i] == 0 {
+			seeds[i] = r.Uint64()
+		}
+	}
+
+	scorer := Scorer{Weights: DefaultWeights, Pairs: input.Pairs, Disappointed: input.Disappointed}
+	if input.Weights != nil {
+		scorer.Weights = *input.Weights
+	}
+
+	results := make([]Result, runs)
+	scores := make([]Score, runs)
+	errs := make([]error, runs)
+
+	// Run on a fixed number of goroutines; each run only reads the shared input
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runs, runtime.GOMAXPROCS(0)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				run := input
+				run.Seed = seeds[i]
+				results[i], errs[i] = Assign(run)
+				if errs[i] == nil {
+					scores[i] = scorer.Score(results[i].Assignments)
+				}
+			}
+		}()
+	}
+	for i := range runs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	// Pick the best in run order so ties always go to the earliest run
+	for i := range runs {
+		if errs[i] != nil {
+			return search, errs[i]
+		}
+		search.Scores = append(search.Scores, scores[i].Total)
+		if search.BestRun == 0 || scores[i].Total > search.BestScore.Total {
+			search.Best = results[i]
+			search.BestScore = scores[i]
+			search.BestRun = i + 1
+		}
+	}
+	return search, nil
+}
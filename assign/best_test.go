@@ -0,0 +1,95 @@
+package assign
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// searchInput is a session with more students than very interested places, so runs differ by seed
+func searchInput() Input {
+	areas := []string{"art", "games", "music"}
+	levels := []model.Interest{model.VeryInterested, model.Interested, model.NotInterested}
+	input := Input{
+		Session: 1,
+		Seed:    42,
+		Classes: []model.Class{
+			testClass("a1", "art", 1, 6, 3),
+			testClass("a2", "art", 1, 6, 3),
+			testClass("g1", "games", 1, 6, 4),
+			testClass("m1", "music", 1, 6, 4),
+		},
+		Pairs: []model.StudentPair{{StudentA: "Student 1", StudentB: "Student 2", Relation: model.Together, Strength: model.Soft}},
+	}
+	streams := []string{"green", "blue"}
+	for i := range 14 {
+		addStudent(&input, fmt.Sprint("Student ", i), 1+i%6, streams[i%2], areas, levels[i%2], levels[(i/2)%3], levels[(i+1)%3])
+	}
+	return input
+}
+
+func TestAssignBestDeterministic(t *testing.T) {
+	input := searchInput()
+	first, err := AssignBest(input, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := AssignBest(input, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Seed != input.Seed {
+		t.Errorf("search seed = %d, want %d", first.Seed, input.Seed)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("searches with the same seed differ: run %d scored %v, then run %d scored %v", first.BestRun, first.Scores, second.BestRun, second.Scores)
+	}
+}
+
+func TestAssignBestKeepsBestRun(t *testing.T) {
+	input := searchInput()
+	search, err := AssignBest(input, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if search.Runs() != 12 {
+		t.Fatalf("got %d runs, want 12", search.Runs())
+	}
+	if search.BestScore.Total != search.Max() {
+		t.Errorf("best score %v, want the highest run score %v", search.BestScore.Total, search.Max())
+	}
+	if search.Scores[search.BestRun-1] != search.Max() || slices.Index(search.Scores, search.Max()) != search.BestRun-1 {
+		t.Errorf("best run %d of %v, want the first run with the highest score", search.BestRun, search.Scores)
+	}
+	if search.Min() == search.Max() {
+		t.Errorf("every run scored %v, want runs that differ so the best run matters", search.Max())
+	}
+
+	scorer := Scorer{Weights: DefaultWeights, Pairs: input.Pairs, Disappointed: input.Disappointed}
+	if got := scorer.Score(search.Best.Assignments).Total; got != search.BestScore.Total {
+		t.Errorf("best assignments score %v, want the recorded %v", got, search.BestScore.Total)
+	}
+}
+
+func TestAssignBestRunSeedRepeats(t *testing.T) {
+	input := searchInput()
+	search, err := AssignBest(input, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := input
+	run.Seed = search.Best.Seed
+	result, err := Assign(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, search.Best) {
+		t.Errorf("run with seed %d differs from the best run of the search", run.Seed)
+	}
+}
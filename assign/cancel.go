@@ -0,0 +1,79 @@
+package assign
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// Cancellation suggests cancelling a class that did not reach its minimum enrollment
+type Cancellation struct {
+	Class    model.Class
+	Enrolled int
+
+	// Affected are the students of the class, placed where they would go if the class were cancelled.
+	// Students with no other class that fits go to the Fallback class.
+	Affected []model.Assignment
+}
+
+// suggestCancellations finds the classes below their minimum enrollment and works out where their
+// students would go if those classes were cancelled: the class with room they are most interested in,
+// other than the classes being cancelled. Classes holding locked students, such as manual assignments
+// or a leader's children, are never suggested and get a warning instead. The placements are left as
+// they were.
+func suggestCancellations(session int, courses []*course, exclusions map[string][]*course, locked map[*student]bool, rules pairRules) ([]Cancellation, []string) {
+	var under []*course
+	var warnings []string
+	for _, c := range courses[1:] {
+		if c.StudentCapacityMin == 0 || len(c.students) >= c.StudentCapacityMin {
+			continue
+		}
+		if slices.ContainsFunc(c.students, func(s *student) bool { return locked[s] }) {
+			warnings = append(warnings, fmt.Sprintf("Class %s is below its minimum of %d students but is not suggested for cancellation, it has students placed there on purpose",
+				c.Name, c.StudentCapacityMin))
+			continue
+		}
+		under = append(under, c)
+	}
+
+	type move struct {
+		student *student
+		from    *course
+		index   int
+		to      *course
+	}
+	var moves []move
+	var suggestions []Cancellation
+	for _, c := range under {
+		suggestion := Cancellation{Class: c.Class, Enrolled: len(c.students)}
+		for len(c.students) > 0 {
+			s := c.students[0]
+			index := c.remove(s)
+
+			to, best := courses[0], -1
+			for _, other := range courses[1:] {
+				if slices.Contains(under, other) || other.capacity <= 0 || !other.AcceptsGrade(s.Grade) ||
+					slices.Contains(exclusions[nameKey(s.name)], other) || rules.conflicts(s, other) {
+					continue
+				}
+				if score := interestScore(s.interestIn(other)); score > best {
+					to, best = other, score
+				}
+			}
+
+			to.assign(s)
+			moves = append(moves, move{student: s, from: c, index: index, to: to})
+			suggestion.Affected = append(suggestion.Affected, assignmentOf(s, to, session))
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	// Put everyone back, undoing the moves in reverse so each student returns to their old position
+	for i := len(moves) - 1; i >= 0; i-- {
+		m := moves[i]
+		m.to.remove(m.student)
+		m.from.insert(m.student, m.index)
+	}
+	return suggestions, warnings
+}
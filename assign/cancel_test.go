@@ -0,0 +1,99 @@
+package assign
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+func TestSuggestCancellationsUndo(t *testing.T) {
+	fallback := &course{Class: model.Class{Name: FallbackName, Session: 1, GradeMax: 999, StudentCapacity: 999}, capacity: 999}
+	courses := []*course{fallback}
+	for i, class := range []model.Class{
+		testClass("a1", "art", 1, 6, 4),
+		testClass("a2", "art", 1, 6, 4),
+		testClass("g1", "games", 1, 6, 2),
+		testClass("m1", "music", 4, 6, 4),
+	} {
+		class.StudentCapacityMin = []int{3, 3, 0, 0}[i]
+		courses = append(courses, &course{Class: class, capacity: class.StudentCapacity})
+	}
+
+	// Two under-filled art classes whose students compete for the few places left elsewhere
+	areas := []string{"art", "games", "music"}
+	for i, c := range []*course{courses[1], courses[1], courses[2], courses[2], courses[3], courses[4], fallback} {
+		s := &student{Student: model.Student{FirstName: "Student", LastName: fmt.Sprint(i), Grade: 2 + i%4}}
+		s.name = s.FullName()
+		for _, area := range areas {
+			s.preferences = append(s.preferences, model.AreaInterest{Area: area, Level: model.Interested})
+		}
+		c.assign(s)
+	}
+
+	before := courseAssignments(courses, 1)
+	capacities := make([]int, len(courses))
+	for i, c := range courses {
+		capacities[i] = c.capacity
+	}
+
+	suggestions, warnings := suggestCancellations(1, courses, nil, make(map[*student]bool), pairRules{})
+	if len(suggestions) != 2 || len(warnings) != 0 {
+		t.Fatalf("got %d suggestions and warnings %q, want both art classes and no warnings", len(suggestions), warnings)
+	}
+	for _, suggestion := range suggestions {
+		if len(suggestion.Affected) != suggestion.Enrolled {
+			t.Errorf("class %s: got %d affected students for %d enrolled", suggestion.Class.ID, len(suggestion.Affected), suggestion.Enrolled)
+		}
+	}
+
+	if after := courseAssignments(courses, 1); !reflect.DeepEqual(after, before) {
+		t.Errorf("assignments changed after the suggestions:\n got %v\nwant %v", after, before)
+	}
+	for i, c := range courses {
+		if c.capacity != capacities[i] {
+			t.Errorf("class %q has %d places left, want %d", c.ID, c.capacity, capacities[i])
+		}
+		for _, s := range c.students {
+			if s.course != c {
+				t.Errorf("%s is listed in %q but placed in %q", s.name, c.ID, s.course.ID)
+			}
+		}
+	}
+}
+
+func TestSuggestCancellationsKeepsLockedClasses(t *testing.T) {
+	areas := []string{"art", "games"}
+	input := Input{
+		Session: 1,
+		Seed:    1,
+		Classes: []model.Class{
+			testClass("a1", "art", 1, 6, 4),
+			testClass("a2", "art", 1, 6, 4),
+			testClass("g1", "games", 1, 6, 4),
+		},
+		Manual: []model.ClassStudent{{ClassID: "a1", StudentFullName: "Ada A"}},
+	}
+	input.Classes[0].StudentCapacityMin = 3
+	input.Classes[1].StudentCapacityMin = 3
+	addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.NotInterested)
+	addStudent(&input, "Ben B", 3, "green", areas, model.NotInterested, model.VeryInterested)
+	addStudent(&input, "Cal C", 3, "green", areas, model.NotInterested, model.VeryInterested)
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cancellation := range result.Cancellations {
+		if cancellation.Class.ID == "a1" {
+			t.Errorf("class a1 with a manual assignment is suggested for cancellation")
+		}
+	}
+	if !hasWarning(result.Warnings, "Class Class a1 is below its minimum") {
+		t.Errorf("got warnings %q, want one for class a1", result.Warnings)
+	}
+	if placements(result.Assignments)["Ada A"] != "a1" {
+		t.Errorf("Ada moved out of the manually assigned class a1")
+	}
+}
@@ -0,0 +1,125 @@
+package assign
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// ReadInput reads the assignment input files from a data directory, using the same file names as
+// the sortinghat script. The manual assignment, exclusion and skip files are optional, as are the
+// pair requests, the adults and adult class assignments used to place leaders' children, and the
+// history of earlier sessions in the history directory.
+func ReadInput(dir string, session int) (Input, error) {
+	input := Input{Session: session}
+	var err error
+
+	input.Classes, err = model.ReadClasses(filepath.Join(dir, "class_catalog.csv"))
+	if err != nil {
+		return input, err
+	}
+	input.Students, err = model.ReadStudents(filepath.Join(dir, "student_list.csv"))
+	if err != nil {
+		return input, err
+	}
+	input.Preferences, _, err = model.ReadStudentPreferences(filepath.Join(dir, "student_preferences.csv"))
+	if err != nil {
+		return input, err
+	}
+
+	manualFile := filepath.Join(dir, "class_assignments_manual.csv")
+	if exists(manualFile) {
+		input.Manual, err = model.ReadClassStudents(manualFile)
+		if err != nil {
+			return input, err
+		}
+	}
+
+	exclusionsFile := filepath.Join(dir, "class_exclusions_manual.csv")
+	if exists(exclusionsFile) {
+		input.Exclusions, err = model.ReadClassStudents(exclusionsFile)
+		if err != nil {
+			return input, err
+		}
+	}
+
+	skipFile := filepath.Join(dir, "skip_assignments_manual.csv")
+	if exists(skipFile) {
+		input.Skips, err = model.ReadSkips(skipFile)
+		if err != nil {
+			return input, err
+		}
+	}
+
+	adultsFile := filepath.Join(dir, "adults.csv")
+	leadersFile := filepath.Join(dir, "adult_class_assignments.csv")
+	if exists(adultsFile) && exists(leadersFile) {
+		input.Adults, _, err = model.ReadAdults(adultsFile)
+		if err != nil {
+			return input, err
+		}
+		input.AdultAssignments, err = model.ReadAdultAssignments(leadersFile)
+		if err != nil {
+			return input, err
+		}
+	}
+
+	pairsFile := filepath.Join(dir, "pair_requests.csv")
+	if exists(pairsFile) {
+		input.Pairs, err = model.ReadStudentPairs(pairsFile)
+		if err != nil {
+			return input, err
+		}
+	}
+
+	input.History, err = ReadHistory(filepath.Join(dir, "history"), session)
+	if err != nil {
+		return input, err
+	}
+	input.Disappointed = Disappointed(input.History)
+
+	return input, nil
+}
+
+// RunFile is the settings file recorded next to an assignments file,
+// e.g. final_assignments.csv -> final_assignments_run.csv
+func RunFile(assignmentsFile string) string {
+	return strings.TrimSuffix(assignmentsFile, filepath.Ext(assignmentsFile)) + "_run.csv"
+}
+
+// WriteRun records the seed and settings of an assignment run so the same inputs can be assigned
+// again with byte-identical results. Extra settings, such as those of a search, are recorded after them.
+func WriteRun(file string, input Input, result Result, extra ...model.Setting) error {
+	mode := input.Mode
+	if mode == "" {
+		mode = Greedy
+	}
+	weights := ""
+	if input.Weights != nil {
+		weights = input.Weights.String()
+	}
+
+	settings := []model.Setting{
+		{Name: "seed", Value: strconv.FormatUint(result.Seed, 10)},
+		{Name: "session", Value: strconv.Itoa(input.Session)},
+		{Name: "mode", Value: string(mode)},
+		{Name: "weights", Value: weights},
+		{Name: "cancel", Value: strings.Join(input.Cancel, ";")},
+	}
+	return model.WriteSettings(file, append(settings, extra...))
+}
+
+// ReadRun reads back the seed and settings recorded by WriteRun
+func ReadRun(file string) ([]model.Setting, error) {
+	return model.ReadSettings(file)
+}
+
+func exists(file string) bool {
+	_, err := os.Stat(file)
+	return !errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,95 @@
+package assign
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+func TestSameSeedWritesIdenticalAssignments(t *testing.T) {
+	dir := t.TempDir()
+	for _, mode := range []Mode{Greedy, Optimal} {
+		input := searchInput()
+		input.Mode = mode
+		input.Weights = &DefaultWeights
+
+		var files [][]byte
+		for i, file := range []string{"first.csv", "second.csv"} {
+			result, err := Assign(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Seed != input.Seed {
+				t.Errorf("%s run %d: seed = %d, want %d", mode, i+1, result.Seed, input.Seed)
+			}
+			file = filepath.Join(dir, string(mode)+"-"+file)
+			err = model.WriteAssignments(file, result.Assignments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			contents, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, contents)
+		}
+		if !bytes.Equal(files[0], files[1]) {
+			t.Errorf("%s: runs with seed %d wrote different assignments", mode, input.Seed)
+		}
+	}
+}
+
+func TestRunFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	input := searchInput()
+	input.Mode = Optimal
+	input.Weights = &Weights{Interest: 1, StreamBalance: 0.5, GradeSpread: 0, Pairs: 2, Disappointment: 0.25}
+	input.Cancel = []string{"a2", "m1"}
+	result := Result{Seed: 1234567890123456789}
+
+	file := RunFile(filepath.Join(dir, "final_assignments.csv"))
+	if got, want := filepath.Base(file), "final_assignments_run.csv"; got != want {
+		t.Errorf("RunFile = %s, want %s", got, want)
+	}
+	err := WriteRun(file, input, result, model.Setting{Name: "runs", Value: "40"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := ReadRun(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []model.Setting{
+		{Name: "seed", Value: "1234567890123456789"},
+		{Name: "session", Value: "1"},
+		{Name: "mode", Value: "optimal"},
+		{Name: "weights", Value: "interest=1,stream=0.5,grade=0,friends=2,disappointment=0.25"},
+		{Name: "cancel", Value: "a2;m1"},
+		{Name: "runs", Value: "40"},
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("ReadRun = %v, want %v", settings, want)
+	}
+
+	// Writing the settings read back gives the same file
+	before, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = model.WriteSettings(file, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("run file changed when written back:\n%s\nwant\n%s", after, before)
+	}
+}
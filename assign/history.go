@@ -0,0 +1,107 @@
+package assign
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// HistoryFile is the file in the history directory holding the final assignments of a session
+func HistoryFile(dir string, session int) string {
+	return filepath.Join(dir, fmt.Sprintf("final_assignments_session_%d.csv", session))
+}
+
+// ReadHistory reads the final assignments of the sessions before the given session from the history
+// directory. Sessions without a file are skipped, so the first session of a term has no history.
+func ReadHistory(dir string, before int) ([]model.Assignment, error) {
+	var history []model.Assignment
+	for session := 1; session < before; session++ {
+		file := HistoryFile(dir, session)
+		if !exists(file) {
+			continue
+		}
+		assignments, err := model.ReadAssignments(file)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, assignments...)
+	}
+	return history, nil
+}
+
+// SaveHistory stores final assignments in the history directory, one file per session, replacing any
+// earlier assignments of the same sessions. It returns the sessions saved.
+func SaveHistory(dir string, assignments []model.Assignment) ([]int, error) {
+	bySession := make(map[int][]model.Assignment)
+	var sessions []int
+	for _, a := range assignments {
+		if _, exists := bySession[a.ClassSession]; !exists {
+			sessions = append(sessions, a.ClassSession)
+		}
+		bySession[a.ClassSession] = append(bySession[a.ClassSession], a)
+	}
+	slices.Sort(sessions)
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		err = model.WriteAssignments(HistoryFile(dir, session), bySession[session])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sessions, nil
+}
+
+// earlierSessions returns the assignments of the sessions before the given session, so a session is never
+// held against itself or against a session that came before it
+func earlierSessions(history []model.Assignment, session int) []model.Assignment {
+	var earlier []model.Assignment
+	for _, a := range history {
+		if a.ClassSession < session {
+			earlier = append(earlier, a)
+		}
+	}
+	return earlier
+}
+
+// pastInterestArea looks up the interest area of an earlier assignment in the full class catalog, by the
+// class ID within its session, or else by the class name in any session in case the class was renumbered.
+// The second result is false when no class matches.
+func pastInterestArea(classes []model.Class, past model.Assignment) (string, bool) {
+	for _, class := range classes {
+		if class.Session == past.ClassSession && class.ID == past.ClassID {
+			return class.InterestArea, true
+		}
+	}
+	for _, class := range classes {
+		if strings.EqualFold(strings.TrimSpace(class.Name), strings.TrimSpace(past.ClassName)) {
+			return class.InterestArea, true
+		}
+	}
+	return "", false
+}
+
+// latestAssignments returns each student's assignment in the most recent session of the history
+func latestAssignments(history []model.Assignment) map[string]model.Assignment {
+	latest := make(map[string]model.Assignment)
+	for _, a := range history {
+		key := nameKey(a.StudentFullName)
+		if previous, exists := latest[key]; !exists || a.ClassSession >= previous.ClassSession {
+			latest[key] = a
+		}
+	}
+	return latest
+}
+
+// priorDeficit is how far a previous assignment fell short of a very interested class, from 0 for a very
+// interested class to maxInterestScore for the Fallback class or a class the student was not interested in
+func priorDeficit(a model.Assignment) int {
+	return maxInterestScore - assignmentInterestScore(a)
+}
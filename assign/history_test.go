@@ -0,0 +1,176 @@
+package assign
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// pastAssignment is a final assignment of an earlier session
+func pastAssignment(session int, classID, className, student string, interest model.Interest) model.Assignment {
+	return model.Assignment{ClassSession: session, ClassID: classID, ClassName: className, StudentFullName: student, StudentInterest: string(interest)}
+}
+
+func TestReadHistoryEarlierSessions(t *testing.T) {
+	dir := t.TempDir()
+	saved, err := SaveHistory(dir, []model.Assignment{
+		pastAssignment(3, "S3-01", "Clay", "Ada A", model.Interested),
+		pastAssignment(1, "S1-01", "Games", "Ada A", model.VeryInterested),
+		pastAssignment(2, "S2-01", "Drums", "Ada A", model.NotInterested),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(saved, want) {
+		t.Errorf("SaveHistory saved sessions %v, want %v", saved, want)
+	}
+
+	history, err := ReadHistory(dir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sessions []int
+	for _, a := range history {
+		sessions = append(sessions, a.ClassSession)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(sessions, want) {
+		t.Errorf("ReadHistory before session 3 read sessions %v, want %v", sessions, want)
+	}
+}
+
+func TestAssignIgnoresLaterHistory(t *testing.T) {
+	// History passed in directly may hold the current and later sessions, which must not count
+	areas := []string{"art", "games"}
+	input := Input{
+		Session: 2,
+		Seed:    1,
+		Classes: []model.Class{
+			{ID: "art", Session: 2, Name: "Painting", InterestArea: "art", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+			{ID: "games", Session: 2, Name: "Chess", InterestArea: "games", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+		},
+		History: []model.Assignment{
+			pastAssignment(2, "art", "Painting", "Ada A", model.VeryInterested),
+			pastAssignment(3, "art", "Painting", "Ada A", model.VeryInterested),
+		},
+	}
+	addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.NotInterested)
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := placements(result.Assignments)["Ada A"]; got != "art" {
+		t.Errorf("Ada is in %q, want art since only later sessions had it", got)
+	}
+}
+
+func TestAssignDoesNotRepeatClassOrArea(t *testing.T) {
+	areas := []string{"art", "games", "music"}
+	input := Input{
+		Session: 2,
+		Seed:    1,
+		Classes: []model.Class{
+			// Session 1 is in the catalog under the same IDs as session 2
+			{ID: "01", Session: 1, Name: "Painting", InterestArea: "art", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+			{ID: "02", Session: 1, Name: "Chess", InterestArea: "games", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+			{ID: "01", Session: 2, Name: "Clay", InterestArea: "art", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+			{ID: "02", Session: 2, Name: "Chess", InterestArea: "games", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+			{ID: "03", Session: 2, Name: "Drums", InterestArea: "music", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+		},
+		History: []model.Assignment{
+			// Ada had painting, so no more art; Ben had chess, renumbered since, so no more games
+			pastAssignment(1, "01", "Painting", "Ada A", model.VeryInterested),
+			pastAssignment(1, "07", "Chess", "Ben B", model.VeryInterested),
+		},
+	}
+	addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.Interested, model.NotInterested)
+	addStudent(&input, "Ben B", 3, "green", areas, model.NotInterested, model.VeryInterested, model.Interested)
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Ada A": "02", "Ben B": "03"}
+	if got := placements(result.Assignments); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if hasWarning(result.Warnings, "Could not find the interest area") {
+		t.Errorf("got warnings %q, want the areas found in the catalog", result.Warnings)
+	}
+}
+
+func TestAssignWarnsOnUnknownPastArea(t *testing.T) {
+	areas := []string{"art"}
+	input := Input{
+		Session: 2,
+		Seed:    1,
+		Classes: []model.Class{{ID: "01", Session: 2, Name: "Clay", InterestArea: "art", GradeMin: 1, GradeMax: 6, StudentCapacity: 5}},
+		History: []model.Assignment{pastAssignment(1, "09", "Origami", "Ada A", model.VeryInterested)},
+	}
+	addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested)
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasWarning(result.Warnings, "Could not find the interest area of Origami from session 1") {
+		t.Errorf("got warnings %q, want one for the class missing from the catalog", result.Warnings)
+	}
+	if got := placements(result.Assignments)["Ada A"]; got != "01" {
+		t.Errorf("Ada is in %q, want 01", got)
+	}
+}
+
+func TestAssignPrefersDisappointedStudents(t *testing.T) {
+	// Ada is pickier, so without history Ada would be placed first and take the one art place
+	areas := []string{"art", "games"}
+	for _, mode := range []Mode{Greedy, Optimal} {
+		for seed := uint64(1); seed <= 10; seed++ {
+			input := Input{
+				Session: 2,
+				Seed:    seed,
+				Mode:    mode,
+				Classes: []model.Class{
+					{ID: "art", Session: 2, Name: "Clay", InterestArea: "art", GradeMin: 1, GradeMax: 6, StudentCapacity: 1},
+					{ID: "games", Session: 2, Name: "Chess", InterestArea: "games", GradeMin: 1, GradeMax: 6, StudentCapacity: 1},
+					{ID: "music", Session: 1, Name: "Drums", InterestArea: "music", GradeMin: 1, GradeMax: 6, StudentCapacity: 5},
+				},
+				History: []model.Assignment{
+					pastAssignment(1, "music", "Drums", "Ada A", model.VeryInterested),
+					pastAssignment(1, "", FallbackName, "Ben B", model.NotInterested),
+				},
+			}
+			addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.NotInterested)
+			addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested, model.Interested)
+
+			result, err := Assign(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := placements(result.Assignments)["Ben B"]; got != "art" {
+				t.Errorf("%s seed %d: Ben is in %q, want art after the Fallback class last session", mode, seed, got)
+			}
+		}
+	}
+}
+
+func TestDisappointed(t *testing.T) {
+	history := []model.Assignment{
+		pastAssignment(1, "", FallbackName, "Ada A", model.NotInterested),
+		pastAssignment(2, "01", "Clay", "Ada A", model.VeryInterested),
+		pastAssignment(1, "01", "Clay", "Ben B", model.VeryInterested),
+		pastAssignment(2, "02", "Chess", "Ben B", model.NotInterested),
+		pastAssignment(2, "03", "Drums", "Cal C", model.Interested),
+	}
+	if got, want := Disappointed(history), []string{"Ben B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Disappointed = %v, want %v", got, want)
+	}
+
+	latest := latestAssignments(history)
+	for name, want := range map[string]int{"ada a": 0, "ben b": maxInterestScore, "cal c": 1} {
+		if got := priorDeficit(latest[name]); got != want {
+			t.Errorf("priorDeficit of %s = %d, want %d", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package assign
+
+import (
+	"slices"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// maxImprovePasses caps the local search, each pass tries every move and swap once
+const maxImprovePasses = 20
+
+// minImprovement ignores score changes that are only floating point noise
+const minImprovement = 1e-9
+
+// improve runs a local search over the placements, moving a student to another class or swapping two
+// students whenever that raises the weighted score. Moves and swaps keep to the class capacity, grade
+// range, exclusions and hard apart requests, and manually assigned students and hard together groups
+// stay where they are. Students are only moved out of the Fallback class, never into it. Each move or
+// swap only rescores the two classes and the students it changes, see scoreState.
+func improve(scorer Scorer, session int, courses []*course, students []*student, locked map[*student]bool, exclusions map[string][]*course, rules pairRules) {
+	state := newScoreState(scorer, session, courses)
+	best := state.total()
+	better := func(a, b *course, moved ...*student) bool {
+		state.update(a, b, moved...)
+		total := state.total()
+		if total > best+minImprovement {
+			best = total
+			return true
+		}
+		return false
+	}
+	allowed := func(s *student, c *course) bool {
+		return c.AcceptsGrade(s.Grade) && !slices.Contains(exclusions[nameKey(s.name)], c) && !rules.conflicts(s, c)
+	}
+
+	for range maxImprovePasses {
+		improved := false
+
+		// Move single students into a class with room
+		for _, s := range students {
+			if locked[s] {
+				continue
+			}
+			for _, c := range courses[1:] {
+				if c == s.course || c.capacity <= 0 || !allowed(s, c) {
+					continue
+				}
+				from := s.course
+				index := from.remove(s)
+				c.assign(s)
+				if better(from, c, s) {
+					improved = true
+					break
+				}
+				c.remove(s)
+				from.insert(s, index)
+				state.update(from, c, s)
+			}
+		}
+
+		// Swap two students between classes
+		for i, a := range students {
+			if locked[a] || a.course == courses[0] {
+				continue
+			}
+			for _, b := range students[i+1:] {
+				if locked[b] || b.course == courses[0] || a.course == b.course || !allowed(a, b.course) || !allowed(b, a.course) {
+					continue
+				}
+				swap(a, b)
+				if better(a.course, b.course, a, b) {
+					improved = true
+					continue
+				}
+				swap(a, b)
+				state.update(a.course, b.course, a, b)
+			}
+		}
+
+		if !improved {
+			return
+		}
+	}
+}
+
+// scoreState keeps the running totals of Scorer.Score for the placements in the courses, so a move
+// or swap only rescores the two classes and the pair requests and disappointment of the students it
+// changes. Its total matches Scorer.Score of the course assignments.
+type scoreState struct {
+	scorer     Scorer
+	session    int
+	courses    []*course
+	gradeRange int
+	placed     int // students in any class, the Fallback class included
+
+	classes      map[*course]classTotals
+	pairs        map[*student][]int // the pair requests of each student by index in scorer.Pairs
+	pairStudents [][2]*student      // the students named in each pair request, nil when not found
+	met          []bool
+	metCount     int
+
+	disappointed map[*student]int // how often each student is listed as disappointed
+	inClass      map[*student]bool
+	happy        map[*student]bool
+	counted      int // disappointed students in a class other than Fallback
+	happyCount   int // disappointed students in a class they are very interested in
+}
+
+// classTotals are the interest points of a class and its class level goals weighted by its size
+type classTotals struct {
+	interest int
+	students int
+	stream   float64
+	grade    float64
+}
+
+func newScoreState(scorer Scorer, session int, courses []*course) *scoreState {
+	state := &scoreState{
+		scorer:       scorer,
+		session:      session,
+		courses:      courses,
+		classes:      make(map[*course]classTotals),
+		pairs:        make(map[*student][]int),
+		disappointed: make(map[*student]int),
+		inClass:      make(map[*student]bool),
+		happy:        make(map[*student]bool),
+	}
+
+	// Moves and swaps never change who is placed, so the range of grades is fixed
+	byName := make(map[string]*student)
+	lowGrade, highGrade := 0, 0
+	for _, c := range courses {
+		for _, s := range c.students {
+			if state.placed == 0 || s.Grade < lowGrade {
+				lowGrade = s.Grade
+			}
+			if state.placed == 0 || s.Grade > highGrade {
+				highGrade = s.Grade
+			}
+			state.placed++
+			byName[nameKey(s.name)] = s
+		}
+	}
+	state.gradeRange = highGrade - lowGrade
+
+	for i, pair := range scorer.Pairs {
+		students := [2]*student{byName[nameKey(pair.StudentA)], byName[nameKey(pair.StudentB)]}
+		for _, s := range students {
+			if s != nil {
+				state.pairs[s] = append(state.pairs[s], i)
+			}
+		}
+		state.pairStudents = append(state.pairStudents, students)
+	}
+	state.met = make([]bool, len(scorer.Pairs))
+	for i := range scorer.Pairs {
+		state.updatePair(i)
+	}
+
+	for _, name := range scorer.Disappointed {
+		if s := byName[nameKey(name)]; s != nil {
+			state.disappointed[s]++
+		}
+	}
+	for s := range state.disappointed {
+		state.updateDisappointed(s)
+	}
+
+	for _, c := range courses {
+		state.updateClass(c)
+	}
+	return state
+}
+
+// update rescores the two classes and the students that moved between them
+func (st *scoreState) update(a, b *course, moved ...*student) {
+	st.updateClass(a)
+	st.updateClass(b)
+	for _, s := range moved {
+		for _, i := range st.pairs[s] {
+			st.updatePair(i)
+		}
+		if st.disappointed[s] > 0 {
+			st.updateDisappointed(s)
+		}
+	}
+}
+
+func (st *scoreState) updateClass(c *course) {
+	assignments := make([]model.Assignment, 0, len(c.students))
+	for _, s := range c.students {
+		assignments = append(assignments, assignmentOf(s, c, st.session))
+	}
+	totals := classTotals{students: len(assignments)}
+	for _, a := range assignments {
+		totals.interest += assignmentInterestScore(a)
+	}
+	if c != st.courses[0] && len(assignments) > 0 {
+		totals.stream = classStreamBalance(assignments) * float64(len(assignments))
+		totals.grade = classGradeSpread(assignments, st.gradeRange) * float64(len(assignments))
+	}
+	st.classes[c] = totals
+}
+
+func (st *scoreState) updatePair(i int) {
+	a, b := st.pairStudents[i][0], st.pairStudents[i][1]
+	together := a != nil && b != nil && a.course != st.courses[0] && a.course == b.course
+	met := together == (st.scorer.Pairs[i].Relation != model.Apart)
+	if met != st.met[i] {
+		st.met[i] = met
+		if met {
+			st.metCount++
+		} else {
+			st.metCount--
+		}
+	}
+}
+
+func (st *scoreState) updateDisappointed(s *student) {
+	times := st.disappointed[s]
+	if st.inClass[s] {
+		st.counted -= times
+	}
+	if st.happy[s] {
+		st.happyCount -= times
+	}
+	st.inClass[s] = s.course != st.courses[0]
+	st.happy[s] = st.inClass[s] && s.interestIn(s.course) == model.VeryInterested
+	if st.inClass[s] {
+		st.counted += times
+	}
+	if st.happy[s] {
+		st.happyCount += times
+	}
+}
+
+// total is the weighted score, adding up the class totals in course order like Scorer.Score
+func (st *scoreState) total() float64 {
+	interest, streamBalance, gradeSpread, friends, disappointment := 1.0, 1.0, 1.0, 1.0, 1.0
+
+	points, weighted := 0, 0
+	streamTotal, gradeTotal := 0.0, 0.0
+	for i, c := range st.courses {
+		totals := st.classes[c]
+		points += totals.interest
+		if i > 0 {
+			weighted += totals.students
+			streamTotal += totals.stream
+			gradeTotal += totals.grade
+		}
+	}
+	if st.placed > 0 {
+		interest = float64(points) / float64(maxInterestScore*st.placed)
+	}
+	if weighted > 0 {
+		streamBalance = streamTotal / float64(weighted)
+		gradeSpread = gradeTotal / float64(weighted)
+	}
+	if len(st.scorer.Pairs) > 0 {
+		friends = float64(st.metCount) / float64(len(st.scorer.Pairs))
+	}
+	if st.counted > 0 {
+		disappointment = float64(st.happyCount) / float64(st.counted)
+	}
+
+	w := st.scorer.Weights
+	return interest*w.Interest + streamBalance*w.StreamBalance + gradeSpread*w.GradeSpread + friends*w.Pairs + disappointment*w.Disappointment
+}
+
+// remove takes the student out of the course and returns their position so the move can be undone
+func (c *course) remove(s *student) int {
+	index := slices.Index(c.students, s)
+	c.students = slices.Delete(c.students, index, index+1)
+	c.capacity++
+	s.course = nil
+	return index
+}
+
+// insert puts the student back at their previous position in the course
+func (c *course) insert(s *student, index int) {
+	c.students = slices.Insert(c.students, index, s)
+	c.capacity--
+	s.course = c
+}
+
+// swap exchanges the courses of two students, keeping their positions in the class lists
+func swap(a, b *student) {
+	ca, cb := a.course, b.course
+	ca.students[slices.Index(ca.students, a)] = b
+	cb.students[slices.Index(cb.students, b)] = a
+	a.course, b.course = cb, ca
+}
+
+// courseAssignments lists the placements of every course in course order
+func courseAssignments(courses []*course, session int) []model.Assignment {
+	var assignments []model.Assignment
+	for _, c := range courses {
+		for _, s := range c.students {
+			assignments = append(assignments, assignmentOf(s, c, session))
+		}
+	}
+	return assignments
+}
+
+// assignmentOf is the final assignment of the student to the course
+func assignmentOf(s *student, c *course, session int) model.Assignment {
+	return model.Assignment{
+		ClassName:       c.Name,
+		ClassSession:    session,
+		ClassID:         c.ID,
+		StudentFullName: s.name,
+		StudentGrade:    s.Grade,
+		StudentTeacher:  s.Teacher,
+		StudentStream:   s.Stream,
+		StudentInterest: string(s.interestIn(c)),
+	}
+}
@@ -0,0 +1,182 @@
+package assign
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+func TestImproveKeepsRules(t *testing.T) {
+	areas := []string{"art", "games", "music"}
+	levels := []model.Interest{model.VeryInterested, model.Interested, model.NotInterested}
+	streams := []string{"green", "blue"}
+
+	for seed := uint64(1); seed <= 20; seed++ {
+		r := newRand(seed)
+
+		fallback := &course{Class: model.Class{Name: FallbackName, Session: 1, GradeMax: 999, StudentCapacity: 999}, capacity: 999}
+		courses := []*course{fallback}
+		for i := range 5 {
+			gradeMin := 1 + r.IntN(3)
+			class := testClass(fmt.Sprint(i), areas[i%len(areas)], gradeMin, gradeMin+2+r.IntN(3), 2+r.IntN(4))
+			courses = append(courses, &course{Class: class, capacity: class.StudentCapacity})
+		}
+
+		var students []*student
+		studentsByName := make(map[string]*student)
+		for i := range 18 {
+			s := &student{
+				Student: model.Student{FirstName: "Student", LastName: fmt.Sprint(i), Grade: 1 + r.IntN(6), Stream: streams[r.IntN(2)]},
+			}
+			s.name = s.FullName()
+			for _, area := range areas {
+				s.preferences = append(s.preferences, model.AreaInterest{Area: area, Level: levels[r.IntN(3)]})
+			}
+			students = append(students, s)
+			studentsByName[nameKey(s.name)] = s
+		}
+
+		// Hard apart pairs, soft pairs and exclusions between random students and classes
+		var pairs []model.StudentPair
+		for range 6 {
+			a, b := students[r.IntN(len(students))], students[r.IntN(len(students))]
+			relation, strength := model.Together, model.Soft
+			if r.IntN(2) == 0 {
+				relation, strength = model.Apart, model.Hard
+			}
+			pairs = append(pairs, model.StudentPair{StudentA: a.name, StudentB: b.name, Relation: relation, Strength: strength})
+		}
+		rules := newPairRules(pairs, studentsByName)
+		exclusions := make(map[string][]*course)
+		for range 6 {
+			s := students[r.IntN(len(students))]
+			exclusions[nameKey(s.name)] = append(exclusions[nameKey(s.name)], courses[1+r.IntN(len(courses)-1)])
+		}
+
+		// Start from a valid placement in the first class that takes each student
+		for _, s := range students {
+			for _, c := range courses[1:] {
+				if c.availableTo(s) && !slices.Contains(exclusions[nameKey(s.name)], c) && !rules.conflicts(s, c) {
+					c.assign(s)
+					break
+				}
+			}
+			if s.course == nil {
+				fallback.assign(s)
+			}
+		}
+		locked := make(map[*student]bool)
+		lockedCourse := make(map[*student]*course)
+		for _, s := range students[:4] {
+			locked[s] = true
+			lockedCourse[s] = s.course
+		}
+
+		scorer := Scorer{Weights: DefaultWeights, Pairs: pairs}
+		before := scorer.Score(courseAssignments(courses, 1)).Total
+		improve(scorer, 1, courses, students, locked, exclusions, rules)
+		after := scorer.Score(courseAssignments(courses, 1)).Total
+
+		if after < before {
+			t.Errorf("seed %d: score went down from %v to %v", seed, before, after)
+		}
+		for _, c := range courses[1:] {
+			if len(c.students) > c.StudentCapacity || c.capacity != c.StudentCapacity-len(c.students) {
+				t.Errorf("seed %d: class %s has %d students for %d places, %d left", seed, c.ID, len(c.students), c.StudentCapacity, c.capacity)
+			}
+		}
+		for _, s := range students {
+			if !slices.Contains(s.course.students, s) {
+				t.Errorf("seed %d: %s is not in the list of their class %s", seed, s.name, s.course.ID)
+			}
+			if s.course == fallback {
+				continue
+			}
+			if !s.course.AcceptsGrade(s.Grade) {
+				t.Errorf("seed %d: %s (%d) is in class %s for grades %d to %d", seed, s.name, s.Grade, s.course.ID, s.course.GradeMin, s.course.GradeMax)
+			}
+			if slices.Contains(exclusions[nameKey(s.name)], s.course) {
+				t.Errorf("seed %d: %s is in class %s, which is excluded for them", seed, s.name, s.course.ID)
+			}
+			if rules.conflicts(s, s.course) {
+				t.Errorf("seed %d: %s shares class %s with someone they must be kept apart from", seed, s.name, s.course.ID)
+			}
+		}
+		for s, c := range lockedCourse {
+			if s.course != c {
+				t.Errorf("seed %d: locked %s moved from %s to %s", seed, s.name, c.ID, s.course.ID)
+			}
+		}
+	}
+}
+
+func TestScoreStateMatchesScore(t *testing.T) {
+	areas := []string{"art", "games", "music"}
+	levels := []model.Interest{model.VeryInterested, model.Interested, model.NotInterested}
+	streams := []string{"green", "blue"}
+
+	for seed := uint64(1); seed <= 20; seed++ {
+		r := newRand(seed)
+
+		fallback := &course{Class: model.Class{Name: FallbackName, Session: 1, GradeMax: 999, StudentCapacity: 999}, capacity: 999}
+		courses := []*course{fallback}
+		for i := range 4 {
+			class := testClass(fmt.Sprint(i), areas[i%len(areas)], 1, 6, 20)
+			courses = append(courses, &course{Class: class, capacity: class.StudentCapacity})
+		}
+
+		var students []*student
+		for i := range 15 {
+			s := &student{
+				Student: model.Student{FirstName: "Student", LastName: fmt.Sprint(i), Grade: 1 + r.IntN(6), Stream: streams[r.IntN(2)]},
+			}
+			s.name = s.FullName()
+			for _, area := range areas {
+				s.preferences = append(s.preferences, model.AreaInterest{Area: area, Level: levels[r.IntN(3)]})
+			}
+			courses[r.IntN(len(courses))].assign(s)
+			students = append(students, s)
+		}
+
+		scorer := Scorer{Weights: DefaultWeights}
+		for range 6 {
+			a, b := students[r.IntN(len(students))], students[r.IntN(len(students))]
+			relation := model.Together
+			if r.IntN(2) == 0 {
+				relation = model.Apart
+			}
+			scorer.Pairs = append(scorer.Pairs, model.StudentPair{StudentA: a.name, StudentB: b.name, Relation: relation, Strength: model.Soft})
+		}
+		scorer.Pairs = append(scorer.Pairs, model.StudentPair{StudentA: students[0].name, StudentB: "Nobody Here", Relation: model.Apart})
+		for range 5 {
+			scorer.Disappointed = append(scorer.Disappointed, students[r.IntN(len(students))].name)
+		}
+
+		// Move students into and out of the Fallback class and swap students between classes
+		state := newScoreState(scorer, 1, courses)
+		for step := range 40 {
+			a := students[r.IntN(len(students))]
+			if step%2 == 0 {
+				from, to := a.course, courses[r.IntN(len(courses))]
+				from.remove(a)
+				to.assign(a)
+				state.update(from, to, a)
+			} else {
+				b := students[r.IntN(len(students))]
+				if a.course == b.course {
+					continue
+				}
+				swap(a, b)
+				state.update(a.course, b.course, a, b)
+			}
+
+			want := scorer.Score(courseAssignments(courses, 1)).Total
+			if got := state.total(); math.Abs(got-want) > minImprovement {
+				t.Fatalf("seed %d step %d: incremental total %v, want %v", seed, step, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package assign
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// placeLeaderChildren places the children of each class leader in the leader's class, finding the
+// children through the leader's household. A child's household comes from the student list, or from
+// their sign up form when the list has none. A child is only placed when the class takes their grade, has
+// room, isn't excluded for them and holds no one they must be kept apart from; otherwise a warning says
+// why. Children already placed, such as by a manual assignment, are left where they are. Placed children
+// are locked like manual assignments.
+func placeLeaderChildren(input Input, studentsByName map[string]*student, coursesByID map[string]*course, exclusions map[string][]*course, locked map[*student]bool, rules pairRules) []string {
+	var warnings []string
+
+	formHouseholds := make(map[string]int)
+	for _, pref := range input.Preferences {
+		if pref.HouseholdID != 0 {
+			formHouseholds[nameKey(pref.FullName)] = pref.HouseholdID
+		}
+	}
+	householdOf := func(child model.Student) int {
+		if child.HouseholdID != 0 {
+			return child.HouseholdID
+		}
+		return formHouseholds[nameKey(child.FullName())]
+	}
+
+	for _, leader := range input.AdultAssignments {
+		c, exists := coursesByID[leader.ClassID]
+		if !exists || leader.ClassID == "" {
+			continue
+		}
+
+		var households []int
+		for _, adult := range input.Adults {
+			if adult.HouseholdID != 0 && isAdult(adult, leader) {
+				households = append(households, adult.HouseholdID)
+			}
+		}
+		if len(households) == 0 {
+			continue
+		}
+
+		for _, child := range input.Students {
+			if !slices.Contains(households, householdOf(child)) {
+				continue
+			}
+			s, exists := studentsByName[nameKey(child.FullName())]
+			if !exists || s.course != nil {
+				continue
+			}
+
+			switch {
+			case !c.AcceptsGrade(s.Grade):
+				warnings = append(warnings, fmt.Sprintf("Could not place %s (%d) in their parent %s's class %s, it is for grades %d to %d",
+					s.name, s.Grade, leader.FullName, c.Name, c.GradeMin, c.GradeMax))
+			case slices.Contains(exclusions[nameKey(s.name)], c):
+				warnings = append(warnings, fmt.Sprintf("Could not place %s in their parent %s's class %s, it is excluded for them",
+					s.name, leader.FullName, c.Name))
+			case rules.conflicts(s, c):
+				warnings = append(warnings, fmt.Sprintf("Could not place %s in their parent %s's class %s, it has a student they must be kept apart from",
+					s.name, leader.FullName, c.Name))
+			case c.capacity <= 0:
+				warnings = append(warnings, fmt.Sprintf("Could not place %s in their parent %s's class %s, it is full",
+					s.name, leader.FullName, c.Name))
+			default:
+				c.assign(s)
+				locked[s] = true
+			}
+		}
+	}
+	return warnings
+}
+
+// isAdult reports whether the class leader is the adult, matching by email or else by name
+func isAdult(adult model.Adult, leader model.AdultAssignment) bool {
+	if adult.Email != "" && leader.Email != "" {
+		return strings.EqualFold(strings.TrimSpace(adult.Email), strings.TrimSpace(leader.Email))
+	}
+	return nameKey(adult.FullName) == nameKey(leader.FullName)
+}
@@ -0,0 +1,117 @@
+package assign
+
+import (
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// leaderInput is a session where Pat Lee of household 7 leads the art class. Ada is very interested in
+// games, so Ada only ends up in art as Pat's child.
+func leaderInput() Input {
+	input := Input{
+		Session: 1,
+		Seed:    1,
+		Classes: []model.Class{testClass("art", "art", 1, 4, 3), testClass("games", "games", 1, 6, 5)},
+		Adults: []model.Adult{
+			{ID: 1, HouseholdID: 7, FullName: "Pat Lee", Email: "pat@example.com"},
+			{ID: 2, HouseholdID: 8, FullName: "Sam Roe", Email: "sam@example.com"},
+		},
+		AdultAssignments: []model.AdultAssignment{{ClassID: "art", FullName: "Pat Lee", Email: "PAT@example.com "}},
+	}
+	addStudent(&input, "Ada Lee", 3, "green", []string{"art", "games"}, model.NotInterested, model.VeryInterested)
+	return input
+}
+
+func TestLeaderChildFromStudentList(t *testing.T) {
+	input := leaderInput()
+	input.Students[0].HouseholdID = 7
+	// Ben has no preference form and is only linked to Pat through the student list
+	input.Students = append(input.Students, model.Student{FirstName: "Ben", LastName: "Lee", Grade: 2, HouseholdID: 7})
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	placed := placements(result.Assignments)
+	if placed["Ada Lee"] != "art" || placed["Ben Lee"] != "art" {
+		t.Errorf("got %v, want Ada and Ben in their parent's art class", placed)
+	}
+}
+
+func TestLeaderChildFromForm(t *testing.T) {
+	input := leaderInput()
+	input.Preferences[0].HouseholdID = 7
+
+	result, err := Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := placements(result.Assignments)["Ada Lee"]; got != "art" {
+		t.Errorf("Ada is in %q, want the parent's art class through the form household", got)
+	}
+
+	// The student list takes precedence over the form
+	input.Students[0].HouseholdID = 8
+	result, err = Assign(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := placements(result.Assignments)["Ada Lee"]; got != "games" {
+		t.Errorf("Ada is in %q, want games since the student list puts Ada in another household", got)
+	}
+}
+
+func TestLeaderChildNotPlaced(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(input *Input)
+		warning string
+	}{
+		{
+			name:    "grade range",
+			change:  func(input *Input) { input.Students[0].Grade = 5 },
+			warning: "Could not place Ada Lee (5) in their parent Pat Lee's class Class art, it is for grades 1 to 4",
+		},
+		{
+			name: "full class",
+			change: func(input *Input) {
+				input.Classes[0].StudentCapacity = 1
+				input.Manual = []model.ClassStudent{{ClassID: "art", StudentFullName: "Cal Roe"}}
+			},
+			warning: "Could not place Ada Lee in their parent Pat Lee's class Class art, it is full",
+		},
+		{
+			name: "exclusion",
+			change: func(input *Input) {
+				input.Exclusions = []model.ClassStudent{{ClassID: "art", StudentFullName: "Ada Lee"}}
+			},
+			warning: "Could not place Ada Lee in their parent Pat Lee's class Class art, it is excluded for them",
+		},
+		{
+			name: "hard apart pair",
+			change: func(input *Input) {
+				input.Manual = []model.ClassStudent{{ClassID: "art", StudentFullName: "Cal Roe"}}
+				input.Pairs = []model.StudentPair{{StudentA: "Cal Roe", StudentB: "Ada Lee", Relation: model.Apart, Strength: model.Hard}}
+			},
+			warning: "Could not place Ada Lee in their parent Pat Lee's class Class art, it has a student they must be kept apart from",
+		},
+	}
+	for _, test := range tests {
+		input := leaderInput()
+		input.Students[0].HouseholdID = 7
+		addStudent(&input, "Cal Roe", 3, "blue", []string{"art", "games"}, model.Interested, model.Interested)
+		test.change(&input)
+
+		result, err := Assign(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hasWarning(result.Warnings, test.warning) {
+			t.Errorf("%s: got warnings %q, want %q", test.name, result.Warnings, test.warning)
+		}
+		if got := placements(result.Assignments)["Ada Lee"]; got == "art" {
+			t.Errorf("%s: Ada is in the parent's art class", test.name)
+		}
+	}
+}
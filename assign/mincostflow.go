@@ -0,0 +1,88 @@
+package assign
+
+// flowGraph is a directed graph with edge capacities and costs for solving min-cost flow
+type flowGraph struct {
+	edges []flowEdge
+	adj   [][]int // edge indexes leaving each node
+}
+
+type flowEdge struct {
+	to   int
+	cap  int
+	cost int
+	flow int
+}
+
+func newFlowGraph(nodes int) *flowGraph {
+	return &flowGraph{adj: make([][]int, nodes)}
+}
+
+// addEdge adds an edge and its residual reverse edge, returning the index of the forward edge
+func (g *flowGraph) addEdge(from, to, capacity, cost int) int {
+	g.edges = append(g.edges, flowEdge{to: to, cap: capacity, cost: cost})
+	g.adj[from] = append(g.adj[from], len(g.edges)-1)
+	g.edges = append(g.edges, flowEdge{to: from, cap: 0, cost: -cost})
+	g.adj[to] = append(g.adj[to], len(g.edges)-1)
+	return len(g.edges) - 2
+}
+
+// minCostFlow sends as much flow as possible from source to sink at the lowest total cost using
+// successive shortest paths. Returns the flow and its cost.
+func (g *flowGraph) minCostFlow(source, sink int) (int, int) {
+	const unreachable = int(^uint(0) >> 1)
+	nodes := len(g.adj)
+	totalFlow, totalCost := 0, 0
+
+	for {
+		// Find the cheapest augmenting path with Bellman-Ford, which handles the negative residual costs
+		dist := make([]int, nodes)
+		prevEdge := make([]int, nodes)
+		inQueue := make([]bool, nodes)
+		for i := range dist {
+			dist[i] = unreachable
+			prevEdge[i] = -1
+		}
+		dist[source] = 0
+		queue := []int{source}
+		inQueue[source] = true
+
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			inQueue[node] = false
+
+			for _, e := range g.adj[node] {
+				edge := g.edges[e]
+				if edge.cap-edge.flow <= 0 {
+					continue
+				}
+				if d := dist[node] + edge.cost; d < dist[edge.to] {
+					dist[edge.to] = d
+					prevEdge[edge.to] = e
+					if !inQueue[edge.to] {
+						queue = append(queue, edge.to)
+						inQueue[edge.to] = true
+					}
+				}
+			}
+		}
+
+		if dist[sink] == unreachable {
+			return totalFlow, totalCost
+		}
+
+		// Push as much as the path allows
+		push := unreachable
+		for node := sink; node != source; node = g.edges[prevEdge[node]^1].to {
+			edge := g.edges[prevEdge[node]]
+			push = min(push, edge.cap-edge.flow)
+		}
+		for node := sink; node != source; node = g.edges[prevEdge[node]^1].to {
+			g.edges[prevEdge[node]].flow += push
+			g.edges[prevEdge[node]^1].flow -= push
+		}
+
+		totalFlow += push
+		totalCost += push * dist[sink]
+	}
+}
@@ -0,0 +1,203 @@
+package assign
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// pairRules are the hard pair requests of a session: groups of students who must share a class and
+// students who must not
+type pairRules struct {
+	groups [][]*student
+	apart  map[*student][]*student
+}
+
+// newPairRules collects the hard pair requests between known students. Students linked by together
+// requests, directly or through each other, form one group.
+func newPairRules(pairs []model.StudentPair, studentsByName map[string]*student) pairRules {
+	rules := pairRules{apart: make(map[*student][]*student)}
+	groupOf := make(map[*student]int)
+	for _, pair := range pairs {
+		a, aExists := studentsByName[nameKey(pair.StudentA)]
+		b, bExists := studentsByName[nameKey(pair.StudentB)]
+		if pair.Strength != model.Hard || !aExists || !bExists || a == b {
+			continue
+		}
+
+		if pair.Relation == model.Apart {
+			rules.apart[a] = append(rules.apart[a], b)
+			rules.apart[b] = append(rules.apart[b], a)
+			continue
+		}
+
+		ga, aGrouped := groupOf[a]
+		gb, bGrouped := groupOf[b]
+		switch {
+		case aGrouped && bGrouped && ga != gb:
+			// Merge the later group into the earlier one
+			ga, gb = min(ga, gb), max(ga, gb)
+			for _, s := range rules.groups[gb] {
+				groupOf[s] = ga
+			}
+			rules.groups[ga] = append(rules.groups[ga], rules.groups[gb]...)
+			rules.groups[gb] = nil
+		case aGrouped && !bGrouped:
+			groupOf[b] = ga
+			rules.groups[ga] = append(rules.groups[ga], b)
+		case !aGrouped && bGrouped:
+			groupOf[a] = gb
+			rules.groups[gb] = append(rules.groups[gb], a)
+		case !aGrouped && !bGrouped:
+			groupOf[a], groupOf[b] = len(rules.groups), len(rules.groups)
+			rules.groups = append(rules.groups, []*student{a, b})
+		}
+	}
+	rules.groups = slices.DeleteFunc(rules.groups, func(group []*student) bool { return len(group) == 0 })
+	return rules
+}
+
+// conflicts reports whether the course holds a student the student must be kept apart from.
+// Students are never kept apart in the Fallback class.
+func (p pairRules) conflicts(s *student, c *course) bool {
+	if c.ID == "" {
+		return false
+	}
+	for _, other := range p.apart[s] {
+		if other.course == c {
+			return true
+		}
+	}
+	return false
+}
+
+// placeGroups places each group of students who must share a class before anyone else, in the class
+// the group is most interested in overall. A group with a manually assigned member joins that member's
+// class. Placed members are locked so later steps keep the group together. It returns a warning for each
+// group that could not be placed together.
+func (p pairRules) placeGroups(courses []*course, exclusions map[string][]*course, locked map[*student]bool, r *rand.Rand) []string {
+	var warnings []string
+	for _, group := range p.groups {
+		var target *course
+		split := false
+		for _, s := range group {
+			if s.course == nil {
+				continue
+			}
+			if target != nil && s.course != target {
+				split = true
+			}
+			target = s.course
+		}
+
+		var pending []*student
+		for _, s := range group {
+			if s.course == nil {
+				pending = append(pending, s)
+			}
+		}
+
+		eligible := func(c *course) bool {
+			if c.capacity < len(pending) {
+				return false
+			}
+			for _, s := range pending {
+				if !c.AcceptsGrade(s.Grade) || slices.Contains(exclusions[nameKey(s.name)], c) || p.conflicts(s, c) {
+					return false
+				}
+			}
+			return true
+		}
+
+		if !split && target != nil && target.ID != "" && eligible(target) {
+			for _, s := range pending {
+				target.assign(s)
+				locked[s] = true
+			}
+			continue
+		}
+		if !split && target == nil {
+			// Shuffle so that ties between equally good classes are broken differently on each run
+			candidates := slices.Clone(courses[1:])
+			r.Shuffle(len(candidates), func(i, j int) {
+				candidates[i], candidates[j] = candidates[j], candidates[i]
+			})
+
+			var best *course
+			bestScore := -1
+			for _, c := range candidates {
+				if !eligible(c) {
+					continue
+				}
+				score := 0
+				for _, s := range pending {
+					score += interestScore(s.interestIn(c))
+				}
+				if score > bestScore {
+					best, bestScore = c, score
+				}
+			}
+			if best != nil {
+				for _, s := range pending {
+					best.assign(s)
+					locked[s] = true
+				}
+				continue
+			}
+		}
+
+		names := make([]string, 0, len(group))
+		for _, s := range group {
+			names = append(names, s.name)
+		}
+		warnings = append(warnings, fmt.Sprintf("Could not keep %s together", strings.Join(names, ", ")))
+	}
+	return warnings
+}
+
+// separate moves students out of a class they share with someone they must be kept apart from, into the
+// class they are most interested in that has room. It returns a warning for each pair it could not separate.
+func (p pairRules) separate(pairs []model.StudentPair, studentsByName map[string]*student, courses []*course, exclusions map[string][]*course, locked map[*student]bool) []string {
+	var warnings []string
+	for _, pair := range pairs {
+		a, aExists := studentsByName[nameKey(pair.StudentA)]
+		b, bExists := studentsByName[nameKey(pair.StudentB)]
+		if pair.Strength != model.Hard || pair.Relation != model.Apart || !aExists || !bExists || a.course == nil || !p.conflicts(a, a.course) || a.course != b.course {
+			continue
+		}
+
+		moved := false
+		for _, s := range []*student{b, a} {
+			if locked[s] {
+				continue
+			}
+			var best *course
+			for _, c := range courses[1:] {
+				if c == s.course || c.capacity <= 0 || !c.AcceptsGrade(s.Grade) || slices.Contains(exclusions[nameKey(s.name)], c) || p.conflicts(s, c) {
+					continue
+				}
+				if best == nil || interestScore(s.interestIn(c)) > interestScore(s.interestIn(best)) {
+					best = c
+				}
+			}
+			if best != nil {
+				s.course.remove(s)
+				best.assign(s)
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			warnings = append(warnings, fmt.Sprintf("Could not keep %s and %s apart", a.name, b.name))
+		}
+	}
+	return warnings
+}
+
+// hasSoft reports whether any of the pair requests is soft
+func hasSoft(pairs []model.StudentPair) bool {
+	return slices.ContainsFunc(pairs, func(pair model.StudentPair) bool { return pair.Strength != model.Hard })
+}
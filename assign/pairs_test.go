@@ -0,0 +1,118 @@
+package assign
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// hasWarning reports whether one of the warnings starts with the prefix
+func hasWarning(warnings []string, prefix string) bool {
+	return slices.ContainsFunc(warnings, func(warning string) bool { return strings.HasPrefix(warning, prefix) })
+}
+
+func TestHardTogetherGroupTooLarge(t *testing.T) {
+	areas := []string{"art", "games"}
+	for _, mode := range []Mode{Greedy, Optimal} {
+		input := Input{
+			Session: 1,
+			Seed:    1,
+			Mode:    mode,
+			Classes: []model.Class{testClass("art", "art", 1, 6, 2), testClass("games", "games", 1, 6, 2)},
+			Pairs: []model.StudentPair{
+				{StudentA: "Ada A", StudentB: "Ben B", Relation: model.Together, Strength: model.Hard},
+				{StudentA: "Ben B", StudentB: "Cal C", Relation: model.Together, Strength: model.Hard},
+			},
+		}
+		addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.Interested)
+		addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested, model.Interested)
+		addStudent(&input, "Cal C", 3, "green", areas, model.VeryInterested, model.Interested)
+
+		result, err := Assign(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkLimits(t, input, result)
+		if !hasWarning(result.Warnings, "Could not keep Ada A, Ben B, Cal C together") {
+			t.Errorf("%s: got warnings %q, want one for the group that does not fit", mode, result.Warnings)
+		}
+		if len(result.UnmetPairs) == 0 {
+			t.Errorf("%s: got no unmet pairs, want the group split", mode)
+		}
+	}
+}
+
+func TestHardApartPair(t *testing.T) {
+	areas := []string{"art", "games"}
+	for _, mode := range []Mode{Greedy, Optimal} {
+		for seed := uint64(1); seed <= 10; seed++ {
+			input := Input{
+				Session: 1,
+				Seed:    seed,
+				Mode:    mode,
+				Classes: []model.Class{testClass("art", "art", 1, 6, 4), testClass("games", "games", 1, 6, 4)},
+				Pairs:   []model.StudentPair{{StudentA: "Ada A", StudentB: "Ben B", Relation: model.Apart, Strength: model.Hard}},
+			}
+			addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.NotInterested)
+			addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested, model.Interested)
+
+			result, err := Assign(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkLimits(t, input, result)
+			placed := placements(result.Assignments)
+			if placed["Ada A"] == placed["Ben B"] {
+				t.Errorf("%s seed %d: Ada and Ben are both in %q, want them apart", mode, seed, placed["Ada A"])
+			}
+			if len(result.UnmetPairs) > 0 {
+				t.Errorf("%s seed %d: got unmet pairs %v", mode, seed, result.UnmetPairs)
+			}
+		}
+	}
+}
+
+func TestConflictingPairRules(t *testing.T) {
+	// Ada and Cal must be together through Ben but also apart, so one request can't be met
+	areas := []string{"art", "games"}
+	for _, mode := range []Mode{Greedy, Optimal} {
+		input := Input{
+			Session: 1,
+			Seed:    1,
+			Mode:    mode,
+			Classes: []model.Class{testClass("art", "art", 1, 6, 4), testClass("games", "games", 1, 6, 4)},
+			Pairs: []model.StudentPair{
+				{StudentA: "Ada A", StudentB: "Ben B", Relation: model.Together, Strength: model.Hard},
+				{StudentA: "Ben B", StudentB: "Cal C", Relation: model.Together, Strength: model.Hard},
+				{StudentA: "Ada A", StudentB: "Cal C", Relation: model.Apart, Strength: model.Hard},
+				{StudentA: "Dee D", StudentB: "Ada A", Relation: model.Together, Strength: model.Soft},
+			},
+		}
+		addStudent(&input, "Ada A", 3, "green", areas, model.VeryInterested, model.Interested)
+		addStudent(&input, "Ben B", 3, "green", areas, model.VeryInterested, model.Interested)
+		addStudent(&input, "Cal C", 3, "green", areas, model.VeryInterested, model.Interested)
+		addStudent(&input, "Dee D", 3, "green", areas, model.Interested, model.Interested)
+
+		result, err := Assign(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkLimits(t, input, result)
+
+		placed := placements(result.Assignments)
+		if placed["Ada A"] == "" || placed["Ada A"] != placed["Ben B"] || placed["Ben B"] != placed["Cal C"] {
+			t.Errorf("%s: got %v, want the hard together group in one class", mode, placed)
+		}
+		if !hasWarning(result.Warnings, "Could not keep Ada A and Cal C apart") {
+			t.Errorf("%s: got warnings %q, want one for the apart request", mode, result.Warnings)
+		}
+		if !slices.Contains(result.UnmetPairs, input.Pairs[2]) {
+			t.Errorf("%s: got unmet pairs %v, want the apart request", mode, result.UnmetPairs)
+		}
+		if placed["Dee D"] != placed["Ada A"] {
+			t.Errorf("%s: Dee is in %q and Ada in %q, want the soft request met", mode, placed["Dee D"], placed["Ada A"])
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package assign
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// Weights tunes how much each goal counts toward the total score of an assignment.
+// Every component scores between 0 (worst) and 1 (best) before it is weighted.
+type Weights struct {
+	Interest       float64 // students placed in classes they are interested in
+	StreamBalance  float64 // an even mix of streams within each class
+	GradeSpread    float64 // a narrow range of grades within each class
+	Pairs          float64 // pair requests met, students together or apart as asked
+	Disappointment float64 // students disappointed last session placed in a class they are very interested in
+}
+
+// DefaultWeights favor student interest, then pair requests and last session's disappointed students
+var DefaultWeights = Weights{
+	Interest:       1,
+	StreamBalance:  0.25,
+	GradeSpread:    0.1,
+	Pairs:          0.5,
+	Disappointment: 0.5,
+}
+
+// weightNames are the names used for the weights in ParseWeights and the score breakdown
+var weightNames = []string{"interest", "stream", "grade", "friends", "disappointment"}
+
+func (w *Weights) field(name string) *float64 {
+	switch name {
+	case "interest":
+		return &w.Interest
+	case "stream":
+		return &w.StreamBalance
+	case "grade":
+		return &w.GradeSpread
+	case "friends", "pairs":
+		return &w.Pairs
+	case "disappointment":
+		return &w.Disappointment
+	}
+	return nil
+}
+
+// ParseWeights reads weights from a spec like "interest=1,stream=0.5". Weights left out of the
+// spec keep their DefaultWeights value.
+func ParseWeights(spec string) (Weights, error) {
+	weights := DefaultWeights
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			return weights, fmt.Errorf("invalid weight %q, expected name=value", part)
+		}
+		field := weights.field(strings.ToLower(strings.TrimSpace(name)))
+		if field == nil {
+			return weights, fmt.Errorf("unknown weight %q, expected one of %s", name, strings.Join(weightNames, ", "))
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return weights, fmt.Errorf("invalid weight %q: %w", part, err)
+		}
+		*field = weight
+	}
+	return weights, nil
+}
+
+// String formats the weights as a spec that ParseWeights reads back
+func (w Weights) String() string {
+	parts := make([]string, 0, len(weightNames))
+	for _, name := range weightNames {
+		parts = append(parts, name+"="+strconv.FormatFloat(*w.field(name), 'g', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Scorer rates assignments by the weighted goals
+type Scorer struct {
+	Weights      Weights
+	Pairs        []model.StudentPair // students asked to be together or apart
+	Disappointed []string            // students who got a class they were not interested in last session
+}
+
+// Component is one goal's share of the total score
+type Component struct {
+	Name     string
+	Value    float64 // between 0 and 1
+	Weight   float64
+	Weighted float64
+}
+
+// ClassScore is the breakdown of the class level goals for one class
+type ClassScore struct {
+	ClassID       string
+	ClassName     string
+	Students      int
+	Interest      float64
+	StreamBalance float64
+	GradeSpread   float64
+}
+
+// Score is the total score of an assignment with a breakdown by goal and by class
+type Score struct {
+	Total             float64
+	Components        []Component
+	Classes           []ClassScore
+	UnmetPairs        []model.StudentPair
+	StillDisappointed []string // disappointed students who again did not get a very interested class
+}
+
+// Score rates the assignments. Students in the Fallback class count as not interested and are left
+// out of the class level goals.
+func (s Scorer) Score(assignments []model.Assignment) Score {
+	var score Score
+
+	// Group the students by class, keeping the order the classes first appear in
+	type classKey struct {
+		session int
+		id      string
+	}
+	var keys []classKey
+	classes := make(map[classKey][]model.Assignment)
+	placed := make(map[string]classKey)
+	lowGrade, highGrade := 0, 0
+	for i, a := range assignments {
+		if i == 0 || a.StudentGrade < lowGrade {
+			lowGrade = a.StudentGrade
+		}
+		if i == 0 || a.StudentGrade > highGrade {
+			highGrade = a.StudentGrade
+		}
+		if a.ClassID == "" {
+			continue
+		}
+		key := classKey{session: a.ClassSession, id: a.ClassID}
+		if _, exists := classes[key]; !exists {
+			keys = append(keys, key)
+		}
+		classes[key] = append(classes[key], a)
+		placed[nameKey(a.StudentFullName)] = key
+	}
+
+	// Interest: the average interest score of every student
+	interest := 1.0
+	if len(assignments) > 0 {
+		total := 0
+		for _, a := range assignments {
+			total += assignmentInterestScore(a)
+		}
+		interest = float64(total) / float64(maxInterestScore*len(assignments))
+	}
+
+	// Stream balance and grade spread: averaged over the classes weighted by class size
+	streamBalance, gradeSpread := 1.0, 1.0
+	gradeRange := highGrade - lowGrade
+	weighted := 0
+	streamTotal, gradeTotal := 0.0, 0.0
+	for _, key := range keys {
+		students := classes[key]
+		class := ClassScore{
+			ClassID:       key.id,
+			ClassName:     students[0].ClassName,
+			Students:      len(students),
+			StreamBalance: classStreamBalance(students),
+			GradeSpread:   classGradeSpread(students, gradeRange),
+		}
+		total := 0
+		for _, a := range students {
+			total += assignmentInterestScore(a)
+		}
+		class.Interest = float64(total) / float64(maxInterestScore*len(students))
+		score.Classes = append(score.Classes, class)
+
+		streamTotal += class.StreamBalance * float64(len(students))
+		gradeTotal += class.GradeSpread * float64(len(students))
+		weighted += len(students)
+	}
+	if weighted > 0 {
+		streamBalance = streamTotal / float64(weighted)
+		gradeSpread = gradeTotal / float64(weighted)
+	}
+
+	// Pairs: the share of pair requests met, students asked to be together in the same class and
+	// students asked to be apart not in the same class
+	friends := 1.0
+	if len(s.Pairs) > 0 {
+		met := 0
+		for _, pair := range s.Pairs {
+			a, aPlaced := placed[nameKey(pair.StudentA)]
+			b, bPlaced := placed[nameKey(pair.StudentB)]
+			together := aPlaced && bPlaced && a == b
+			if together == (pair.Relation != model.Apart) {
+				met++
+			} else {
+				score.UnmetPairs = append(score.UnmetPairs, pair)
+			}
+		}
+		friends = float64(met) / float64(len(s.Pairs))
+	}
+
+	// Disappointment: the share of last session's disappointed students who got a very interested class
+	disappointment := 1.0
+	if len(s.Disappointed) > 0 {
+		interests := make(map[string]model.Interest)
+		for _, a := range assignments {
+			if a.ClassID != "" {
+				interests[nameKey(a.StudentFullName)], _ = model.ParseInterest(a.StudentInterest)
+			}
+		}
+		counted, happy := 0, 0
+		for _, name := range s.Disappointed {
+			level, exists := interests[nameKey(name)]
+			if !exists {
+				continue
+			}
+			counted++
+			if level == model.VeryInterested {
+				happy++
+			} else {
+				score.StillDisappointed = append(score.StillDisappointed, name)
+			}
+		}
+		if counted > 0 {
+			disappointment = float64(happy) / float64(counted)
+		}
+	}
+
+	for _, c := range []Component{
+		{Name: "interest", Value: interest, Weight: s.Weights.Interest},
+		{Name: "stream", Value: streamBalance, Weight: s.Weights.StreamBalance},
+		{Name: "grade", Value: gradeSpread, Weight: s.Weights.GradeSpread},
+		{Name: "friends", Value: friends, Weight: s.Weights.Pairs},
+		{Name: "disappointment", Value: disappointment, Weight: s.Weights.Disappointment},
+	} {
+		c.Weighted = c.Value * c.Weight
+		score.Total += c.Weighted
+		score.Components = append(score.Components, c)
+	}
+
+	return score
+}
+
+// Disappointed lists the students whose most recent assignment in the history was in the Fallback class
+// or a class they were not interested in
+func Disappointed(history []model.Assignment) []string {
+	latest := latestAssignments(history)
+	var names []string
+	for _, a := range history {
+		if latest[nameKey(a.StudentFullName)] != a || priorDeficit(a) < maxInterestScore {
+			continue
+		}
+		if !slices.Contains(names, a.StudentFullName) {
+			names = append(names, a.StudentFullName)
+		}
+	}
+	return names
+}
+
+func assignmentInterestScore(a model.Assignment) int {
+	if a.ClassID == "" {
+		return 0
+	}
+	level, _ := model.ParseInterest(a.StudentInterest)
+	return interestScore(level)
+}
+
+// classStreamBalance is 1 when every stream in the class has the same number of students and
+// 0 when the class is all one stream
+func classStreamBalance(students []model.Assignment) float64 {
+	counts := make(map[string]int)
+	for _, a := range students {
+		counts[strings.ToLower(strings.TrimSpace(a.StudentStream))]++
+	}
+	if len(students) < 2 {
+		return 1
+	}
+
+	// Compare against the two streams the school uses so a class that is all one stream scores 0
+	streams := max(len(counts), 2)
+	largest := 0
+	for _, count := range counts {
+		largest = max(largest, count)
+	}
+	even := float64(len(students)) / float64(streams)
+	return 1 - (float64(largest)-even)/(float64(len(students))-even)
+}
+
+// classGradeSpread is 1 when the class is all one grade and 0 when it spans the full range of grades
+func classGradeSpread(students []model.Assignment, gradeRange int) float64 {
+	if gradeRange == 0 {
+		return 1
+	}
+	low, high := students[0].StudentGrade, students[0].StudentGrade
+	for _, a := range students {
+		low = min(low, a.StudentGrade)
+		high = max(high, a.StudentGrade)
+	}
+	return 1 - float64(high-low)/float64(gradeRange)
+}
@@ -0,0 +1,98 @@
+package assign
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+func TestParseWeights(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Weights
+		err  string
+	}{
+		{spec: "", want: DefaultWeights},
+		{spec: "interest=2, stream=0", want: Weights{Interest: 2, StreamBalance: 0, GradeSpread: 0.1, Pairs: 0.5, Disappointment: 0.5}},
+		{spec: "pairs=0.75", want: Weights{Interest: 1, StreamBalance: 0.25, GradeSpread: 0.1, Pairs: 0.75, Disappointment: 0.5}},
+		{spec: "friends=0.75", want: Weights{Interest: 1, StreamBalance: 0.25, GradeSpread: 0.1, Pairs: 0.75, Disappointment: 0.5}},
+		{spec: "height=1", err: "unknown weight"},
+		{spec: "interest", err: "expected name=value"},
+		{spec: "interest=high", err: "invalid weight"},
+	}
+	for _, test := range tests {
+		got, err := ParseWeights(test.spec)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("ParseWeights(%q) error = %v, want %q", test.spec, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseWeights(%q) error = %v", test.spec, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseWeights(%q) = %+v, want %+v", test.spec, got, test.want)
+		}
+	}
+}
+
+func TestParseWeightsString(t *testing.T) {
+	weights := Weights{Interest: 1.5, StreamBalance: 0, GradeSpread: 0.2, Pairs: 3, Disappointment: 0.125}
+	got, err := ParseWeights(weights.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != weights {
+		t.Errorf("ParseWeights(%q) = %+v, want %+v", weights.String(), got, weights)
+	}
+}
+
+// classOf returns assignments to one class for students in the given grades and streams
+func classOf(grades []int, streams []string) []model.Assignment {
+	var assignments []model.Assignment
+	for i := range grades {
+		assignments = append(assignments, model.Assignment{ClassID: "a", StudentGrade: grades[i], StudentStream: streams[i]})
+	}
+	return assignments
+}
+
+func TestClassStreamBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		streams []string
+		want    float64
+	}{
+		{name: "all one stream", streams: []string{"green", "green", "Green ", "green"}, want: 0},
+		{name: "even", streams: []string{"green", "blue", "green", "blue"}, want: 1},
+		{name: "single student", streams: []string{"green"}, want: 1},
+	}
+	for _, test := range tests {
+		got := classStreamBalance(classOf(make([]int, len(test.streams)), test.streams))
+		if got != test.want {
+			t.Errorf("%s: classStreamBalance = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestClassGradeSpread(t *testing.T) {
+	tests := []struct {
+		name       string
+		grades     []int
+		gradeRange int
+		want       float64
+	}{
+		{name: "no grade range", grades: []int{3, 3}, gradeRange: 0, want: 1},
+		{name: "one grade", grades: []int{2, 2, 2}, gradeRange: 4, want: 1},
+		{name: "full range", grades: []int{1, 5}, gradeRange: 4, want: 0},
+		{name: "half range", grades: []int{1, 3}, gradeRange: 4, want: 0.5},
+	}
+	for _, test := range tests {
+		got := classGradeSpread(classOf(test.grades, make([]string, len(test.grades))), test.gradeRange)
+		if got != test.want {
+			t.Errorf("%s: classGradeSpread = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
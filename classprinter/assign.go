@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/christophergm/miniclasses/assign"
+	"github.com/christophergm/miniclasses/model"
+)
+
+// assignOptions are the --assign settings
+type assignOptions struct {
+	Mode    assign.Mode
+	Weights *assign.Weights
+	Seed    uint64
+	Runs    int      // more than one keeps the best scoring of that many runs
+	Cancel  []string // IDs of classes left out
+}
+
+// assignStudents runs the assignment engine on the input files in the data directory and writes the final assignments,
+// recording the seed and settings of the run next to them. With more than one run the best run is kept and the
+// distribution of the run scores is written to the runs report. Classes below their minimum enrollment are
+// written to the cancellations report.
+func assignStudents(dataDir string, session int, options assignOptions, outputFile string, runsFile string, cancellationsFile string) error {
+	input, err := assign.ReadInput(dataDir, session)
+	if err != nil {
+		return err
+	}
+	input.Mode = options.Mode
+	input.Weights = options.Weights
+	input.Seed = options.Seed
+	input.Cancel = options.Cancel
+
+	var result assign.Result
+	var extra []model.Setting
+	if options.Runs > 1 {
+		search, err := assign.AssignBest(input, options.Runs)
+		if err != nil {
+			return err
+		}
+		result = search.Best
+		extra = []model.Setting{
+			{Name: "runs", Value: strconv.Itoa(search.Runs())},
+			{Name: "search_seed", Value: strconv.FormatUint(search.Seed, 10)},
+			{Name: "score", Value: strconv.FormatFloat(search.BestScore.Total, 'f', 3, 64)},
+		}
+
+		err = generateRunsReport(search, runsFile)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Best of %d runs: score %.3f (median %.3f, lowest %.3f).\n",
+			search.Runs(), search.BestScore.Total, search.Median(), search.Min())
+	} else {
+		result, err = assign.Assign(input)
+		if err != nil {
+			return err
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Println(warning)
+	}
+	for _, pair := range result.UnmetPairs {
+		fmt.Printf("Pair request not met (%s %s): %s and %s\n", pair.Strength, pair.Relation, pair.StudentA, pair.StudentB)
+	}
+
+	for _, cancellation := range result.Cancellations {
+		fmt.Printf("Class %s %s has %d of its minimum %d students, consider cancelling it with --cancel %s\n",
+			cancellation.Class.ID, cancellation.Class.Name, cancellation.Enrolled, cancellation.Class.StudentCapacityMin, cancellation.Class.ID)
+	}
+
+	err = generateCancellations(result.Cancellations, cancellationsFile)
+	if err != nil {
+		return err
+	}
+	err = model.WriteAssignments(outputFile, result.Assignments)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Assignment seed: %d\n", result.Seed)
+	return assign.WriteRun(assign.RunFile(outputFile), input, result, extra...)
+}
+
+// Generate a markdown report of the classes below their minimum enrollment, listing where each of their
+// students would go if the class were cancelled
+func generateCancellations(cancellations []assign.Cancellation, outputFile string) error {
+	tmpl, err := loadTemplate("cancellations_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tmpl.Execute(f, cancellations)
+}
+
+// runsBuckets is the number of bars in the score distribution of the runs report
+const runsBuckets = 10
+
+// ScoreBucket is one bar of the score distribution
+type ScoreBucket struct {
+	Low  float64
+	High float64
+	Runs int
+	Best bool // the best run falls in this bucket
+}
+
+// Bar draws the number of runs in the bucket
+func (b ScoreBucket) Bar() string {
+	bar := ""
+	for range b.Runs {
+		bar += "#"
+	}
+	return bar
+}
+
+// scoreBuckets splits the range of run scores into equal width buckets
+func scoreBuckets(search assign.Search) []ScoreBucket {
+	low, high := search.Min(), search.Max()
+	if high == low {
+		return []ScoreBucket{{Low: low, High: high, Runs: search.Runs(), Best: true}}
+	}
+
+	width := (high - low) / runsBuckets
+	buckets := make([]ScoreBucket, runsBuckets)
+	for i := range buckets {
+		buckets[i].Low = low + float64(i)*width
+		buckets[i].High = low + float64(i+1)*width
+	}
+	bucketOf := func(score float64) int {
+		return min(int((score-low)/width), runsBuckets-1)
+	}
+	for _, score := range search.Scores {
+		buckets[bucketOf(score)].Runs++
+	}
+	buckets[bucketOf(search.BestScore.Total)].Best = true
+	return buckets
+}
+
+// Generate a markdown report of a best-of-N search showing how the kept run compares to the others
+func generateRunsReport(search assign.Search, outputFile string) error {
+	tmpl, err := loadTemplate("assignment_runs_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tmpl.Execute(f, struct {
+		assign.Search
+		Buckets []ScoreBucket
+	}{
+		Search:  search,
+		Buckets: scoreBuckets(search),
+	})
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// AttendanceRecord is one row of an attendance CSV: whether a student was present at one meeting
+type AttendanceRecord struct {
+	ClassID         string
+	ClassName       string
+	StudentFullName string
+	Date            string
+	Present         bool
+}
+
+// AttendanceTotal is how many meetings a student attended out of the meetings recorded for them
+type AttendanceTotal struct {
+	StudentFullName string
+	ClassID         string
+	ClassName       string
+	Attended        int
+	Meetings        int
+}
+
+// AttendancePercent is the share of recorded meetings the student attended
+func (t AttendanceTotal) AttendancePercent() int {
+	if t.Meetings == 0 {
+		return 0
+	}
+	return t.Attended * 100 / t.Meetings
+}
+
+var attendanceHeader = []string{"class_id", "class_name", "student_full_name", "date", "present"}
+
+// Generate printable attendance sheets for every class and a blank attendance CSV to fill in,
+// with one column or row per meeting date of the class
+func generateAttendanceSheets(data map[string]ClassData, sheetFile string, csvFile string) error {
+	tmpl, err := loadTemplate("attendance_sheet_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(sheetFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	c, err := os.Create(csvFile)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	writer := csv.NewWriter(c)
+
+	err = writer.Write(attendanceHeader)
+	if err != nil {
+		return err
+	}
+
+	// Extract and sort class IDs
+	classIDs := make([]string, 0, len(data))
+	for classID := range data {
+		classIDs = append(classIDs, classID)
+	}
+	sort.Strings(classIDs)
+
+	for _, classID := range classIDs {
+		class := data[classID]
+		dates := class.Catalog.MeetingDates
+
+		sortStudents(class.Students, classListOrder)
+
+		err := tmpl.Execute(f, struct {
+			ClassData
+			Dates []string
+		}{
+			ClassData: class,
+			Dates:     dates,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, student := range class.Students {
+			for _, date := range dates {
+				err := writer.Write([]string{class.Catalog.ID, class.Catalog.Name, student.StudentFullName, date, ""})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// readAttendance reads a filled-in attendance CSV (columns: class_id, class_name, student_full_name, date, present)
+func readAttendance(file string) ([]AttendanceRecord, error) {
+	records, err := readRecords(file, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	var attendance []AttendanceRecord
+	for i, record := range records {
+		present, err := parsePresent(record[4])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", file, i+2, err)
+		}
+
+		attendance = append(attendance, AttendanceRecord{
+			ClassID:         record[0],
+			ClassName:       record[1],
+			StudentFullName: strings.TrimSpace(record[2]),
+			Date:            record[3],
+			Present:         present,
+		})
+	}
+
+	return attendance, nil
+}
+
+// parsePresent interprets the present column; a blank cell counts as absent
+func parsePresent(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "x", "y", "yes", "1", "true", "present":
+		return true, nil
+	case "", "n", "no", "0", "false", "absent":
+		return false, nil
+	}
+	return false, fmt.Errorf("unrecognized present value %q", value)
+}
+
+// Total the attendance records per student and class
+func totalAttendance(attendance []AttendanceRecord) []AttendanceTotal {
+	type totalKey struct {
+		student string
+		classID string
+	}
+	totalMap := make(map[totalKey]*AttendanceTotal)
+
+	for _, record := range attendance {
+		key := totalKey{student: record.StudentFullName, classID: record.ClassID}
+		if _, exists := totalMap[key]; !exists {
+			totalMap[key] = &AttendanceTotal{
+				StudentFullName: record.StudentFullName,
+				ClassID:         record.ClassID,
+				ClassName:       record.ClassName,
+			}
+		}
+		totalMap[key].Meetings++
+		if record.Present {
+			totalMap[key].Attended++
+		}
+	}
+
+	totals := make([]AttendanceTotal, 0, len(totalMap))
+	for _, total := range totalMap {
+		totals = append(totals, *total)
+	}
+
+	// Sort by fewest meetings attended first so the students who never show up are at the top
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Attended == totals[j].Attended {
+			return strings.ToLower(totals[i].StudentFullName) < strings.ToLower(totals[j].StudentFullName)
+		}
+		return totals[i].Attended < totals[j].Attended
+	})
+
+	return totals
+}
+
+// Generate a markdown report of attendance totals per student
+func generateAttendanceReport(totals []AttendanceTotal, outputFile string) error {
+	tmpl, err := loadTemplate("attendance_report_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var neverAttended []AttendanceTotal
+	for _, total := range totals {
+		if total.Attended == 0 {
+			neverAttended = append(neverAttended, total)
+		}
+	}
+
+	return tmpl.Execute(f, struct {
+		NeverAttended []AttendanceTotal
+		Totals        []AttendanceTotal
+	}{
+		NeverAttended: neverAttended,
+		Totals:        totals,
+	})
+}
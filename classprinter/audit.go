@@ -0,0 +1,34 @@
+package main
+
+// FillPercent is the number of students as a percentage of the class capacity
+func (c ClassData) FillPercent() int {
+	if c.Catalog.StudentCapacity <= 0 {
+		return 0
+	}
+	return len(c.Students) * 100 / c.Catalog.StudentCapacity
+}
+
+// OverCapacity is the number of students beyond the class capacity, or zero if the class fits
+func (c ClassData) OverCapacity() int {
+	over := len(c.Students) - c.Catalog.StudentCapacity
+	if over < 0 {
+		return 0
+	}
+	return over
+}
+
+// UnderMinimum is the number of students the class is short of its minimum enrollment, or zero if it has enough
+func (c ClassData) UnderMinimum() int {
+	return max(c.Catalog.StudentCapacityMin-len(c.Students), 0)
+}
+
+// OutOfGradeRange lists the students whose grade is outside the class grade range
+func (c ClassData) OutOfGradeRange() []FinalAssignment {
+	var students []FinalAssignment
+	for _, student := range c.Students {
+		if !c.Catalog.AcceptsGrade(student.StudentGrade) {
+			students = append(students, student)
+		}
+	}
+	return students
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// A class is flagged as skewed when one stream makes up at least this share of a class
+// with at least streamSkewMinStudents students
+const (
+	streamSkewPercent     = 75
+	streamSkewMinStudents = 4
+)
+
+// LabelCount is the number of students with one stream or grade
+type LabelCount struct {
+	Label string
+	Count int
+}
+
+// StreamCounts counts the class students per stream, sorted by stream
+func (c ClassData) StreamCounts() []LabelCount {
+	counts := make(map[string]int)
+	for _, student := range c.Students {
+		counts[streamKey(student.StudentStream)]++
+	}
+
+	streams := make([]string, 0, len(counts))
+	for stream := range counts {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+
+	result := make([]LabelCount, 0, len(streams))
+	for _, stream := range streams {
+		result = append(result, LabelCount{Label: stream, Count: counts[stream]})
+	}
+	return result
+}
+
+// StreamCount is the number of class students in the stream
+func (c ClassData) StreamCount(stream string) int {
+	count := 0
+	for _, student := range c.Students {
+		if streamKey(student.StudentStream) == streamKey(stream) {
+			count++
+		}
+	}
+	return count
+}
+
+// GradeCounts counts the class students per grade, sorted by grade
+func (c ClassData) GradeCounts() []LabelCount {
+	counts := make(map[int]int)
+	for _, student := range c.Students {
+		counts[student.StudentGrade]++
+	}
+
+	grades := make([]int, 0, len(counts))
+	for grade := range counts {
+		grades = append(grades, grade)
+	}
+	sort.Ints(grades)
+
+	result := make([]LabelCount, 0, len(grades))
+	for _, grade := range grades {
+		result = append(result, LabelCount{Label: gradeLabel(grade), Count: counts[grade]})
+	}
+	return result
+}
+
+// StreamSkewed reports whether one stream dominates the class
+func (c ClassData) StreamSkewed() bool {
+	if len(c.Students) < streamSkewMinStudents {
+		return false
+	}
+	for _, stream := range c.StreamCounts() {
+		if stream.Count*100 >= streamSkewPercent*len(c.Students) {
+			return true
+		}
+	}
+	return false
+}
+
+// GradeSpread is the difference between the highest and lowest grade in the class
+func (c ClassData) GradeSpread() int {
+	if len(c.Students) == 0 {
+		return 0
+	}
+	low, high := c.Students[0].StudentGrade, c.Students[0].StudentGrade
+	for _, student := range c.Students {
+		low = min(low, student.StudentGrade)
+		high = max(high, student.StudentGrade)
+	}
+	return high - low
+}
+
+// allStreams lists every stream found in the classes, sorted
+func allStreams(classes []ClassData) []string {
+	seen := make(map[string]bool)
+	var streams []string
+	for _, class := range classes {
+		for _, stream := range class.StreamCounts() {
+			if !seen[stream.Label] {
+				seen[stream.Label] = true
+				streams = append(streams, stream.Label)
+			}
+		}
+	}
+	sort.Strings(streams)
+	return streams
+}
+
+func streamKey(stream string) string {
+	stream = strings.ToLower(strings.TrimSpace(stream))
+	if stream == "" {
+		return "unknown"
+	}
+	return stream
+}
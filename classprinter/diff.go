@@ -0,0 +1,228 @@
+package main
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/christophergm/miniclasses/assign"
+)
+
+// Kinds of change between two assignment runs
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeMoved   = "moved"
+)
+
+// AssignmentChange is a student whose class differs between two assignment runs
+type AssignmentChange struct {
+	Kind            string
+	StudentFullName string
+	StudentGrade    int
+	StudentTeacher  string
+	Session         int
+	FromClassID     string
+	FromClassName   string
+	ToClassID       string
+	ToClassName     string
+}
+
+// ClassChanges lists the students joining and leaving one class
+type ClassChanges struct {
+	ClassID   string
+	ClassName string
+	Joining   []AssignmentChange
+	Leaving   []AssignmentChange
+}
+
+// TeacherChanges lists the changed students of one teacher
+type TeacherChanges struct {
+	Teacher string
+	Changes []AssignmentChange
+}
+
+// AssignmentDiff is every change between two assignment runs grouped by class and by teacher
+type AssignmentDiff struct {
+	OldFile  string
+	NewFile  string
+	OldRun   string // seed and settings of the old run, empty when it has no run file
+	NewRun   string
+	Added    int
+	Removed  int
+	Moved    int
+	Classes  []ClassChanges
+	Teachers []TeacherChanges
+}
+
+// Compare two assignment runs. Students are matched by full name and session so a student
+// can appear once per session in each run.
+func diffAssignments(oldAssignments, newAssignments []FinalAssignment) []AssignmentChange {
+	type studentKey struct {
+		name    string
+		session int
+	}
+
+	oldMap := make(map[studentKey]FinalAssignment)
+	for _, student := range oldAssignments {
+		oldMap[studentKey{name: strings.ToLower(strings.TrimSpace(student.StudentFullName)), session: student.ClassSession}] = student
+	}
+
+	var changes []AssignmentChange
+	seen := make(map[studentKey]bool)
+	for _, student := range newAssignments {
+		key := studentKey{name: strings.ToLower(strings.TrimSpace(student.StudentFullName)), session: student.ClassSession}
+		seen[key] = true
+
+		change := AssignmentChange{
+			StudentFullName: student.StudentFullName,
+			StudentGrade:    student.StudentGrade,
+			StudentTeacher:  student.StudentTeacher,
+			Session:         student.ClassSession,
+			ToClassID:       student.ClassID,
+			ToClassName:     student.ClassName,
+		}
+
+		old, exists := oldMap[key]
+		if !exists {
+			change.Kind = changeAdded
+			changes = append(changes, change)
+			continue
+		}
+		if old.ClassID != student.ClassID || old.ClassName != student.ClassName {
+			change.Kind = changeMoved
+			change.FromClassID = old.ClassID
+			change.FromClassName = old.ClassName
+			changes = append(changes, change)
+		}
+	}
+
+	for key, old := range oldMap {
+		if seen[key] {
+			continue
+		}
+		changes = append(changes, AssignmentChange{
+			Kind:            changeRemoved,
+			StudentFullName: old.StudentFullName,
+			StudentGrade:    old.StudentGrade,
+			StudentTeacher:  old.StudentTeacher,
+			Session:         old.ClassSession,
+			FromClassID:     old.ClassID,
+			FromClassName:   old.ClassName,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Session == changes[j].Session {
+			return strings.ToLower(changes[i].StudentFullName) < strings.ToLower(changes[j].StudentFullName)
+		}
+		return changes[i].Session < changes[j].Session
+	})
+
+	return changes
+}
+
+// Group the changes by class and by teacher
+func groupChanges(changes []AssignmentChange) AssignmentDiff {
+	var diff AssignmentDiff
+
+	// The Fallback class has no ID so classes are keyed by ID and name
+	classMap := make(map[string]*ClassChanges)
+	classFor := func(id, name string) *ClassChanges {
+		key := id + "\x00" + name
+		if _, exists := classMap[key]; !exists {
+			classMap[key] = &ClassChanges{ClassID: id, ClassName: name}
+		}
+		return classMap[key]
+	}
+
+	teacherMap := make(map[string]*TeacherChanges)
+	for _, change := range changes {
+		switch change.Kind {
+		case changeAdded:
+			diff.Added++
+		case changeRemoved:
+			diff.Removed++
+		case changeMoved:
+			diff.Moved++
+		}
+
+		if change.Kind != changeRemoved {
+			class := classFor(change.ToClassID, change.ToClassName)
+			class.Joining = append(class.Joining, change)
+		}
+		if change.Kind != changeAdded {
+			class := classFor(change.FromClassID, change.FromClassName)
+			class.Leaving = append(class.Leaving, change)
+		}
+
+		if _, exists := teacherMap[change.StudentTeacher]; !exists {
+			teacherMap[change.StudentTeacher] = &TeacherChanges{Teacher: change.StudentTeacher}
+		}
+		teacherMap[change.StudentTeacher].Changes = append(teacherMap[change.StudentTeacher].Changes, change)
+	}
+
+	for _, class := range classMap {
+		diff.Classes = append(diff.Classes, *class)
+	}
+	sort.Slice(diff.Classes, func(i, j int) bool {
+		if diff.Classes[i].ClassID == diff.Classes[j].ClassID {
+			return diff.Classes[i].ClassName < diff.Classes[j].ClassName
+		}
+		return diff.Classes[i].ClassID < diff.Classes[j].ClassID
+	})
+
+	for _, teacher := range teacherMap {
+		diff.Teachers = append(diff.Teachers, *teacher)
+	}
+	sort.Slice(diff.Teachers, func(i, j int) bool {
+		return diff.Teachers[i].Teacher < diff.Teachers[j].Teacher
+	})
+
+	return diff
+}
+
+// Generate a report of the differences between two assignment runs, as HTML when the
+// output file ends in .html and as Markdown otherwise
+// runSummary lists the seed and settings recorded next to an assignments file, such as "seed 42, mode
+// greedy", so a diff shows how each run was made. It is empty when the assignments have no run file.
+func runSummary(assignmentsFile string) (string, error) {
+	file := assign.RunFile(assignmentsFile)
+	if _, err := os.Stat(file); err != nil {
+		return "", nil
+	}
+	settings, err := assign.ReadRun(file)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, setting := range settings {
+		if setting.Value != "" {
+			parts = append(parts, setting.Name+" "+setting.Value)
+		}
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+func generateDiffReport(diff AssignmentDiff, outputFile string) error {
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var tmpl interface {
+		Execute(w io.Writer, data any) error
+	}
+	if strings.HasSuffix(strings.ToLower(outputFile), ".html") {
+		tmpl, err = loadHTMLTemplate("assignment_diff_template.html")
+	} else {
+		tmpl, err = loadTemplate("assignment_diff_template.md")
+	}
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(f, diff)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exportHeader is the column layout of the flat CSV export, see README.md for the schema
+var exportHeader = []string{
+	"class_id", "class_session", "class_name", "interest_area", "grade_min", "grade_max", "student_capacity_max",
+	"location", "meet_location", "student_full_name", "student_grade", "student_teacher", "student_stream",
+	"student_interest", "lead_adults", "lead_adult_emails", "meeting_dates", "start_time", "end_time",
+	"student_capacity_min",
+}
+
+// ClassExport is the top level of the JSON export, see README.md for the schema
+type ClassExport struct {
+	Classes    []ClassData    `json:"classes"`
+	Unassigned UnassignedData `json:"unassigned"`
+}
+
+// Write the joined class data as a flat CSV with one row per student, including students outside the catalog
+func exportCSV(data map[string]ClassData, unassigned UnassignedData, outputFile string) error {
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+
+	err = writer.Write(exportHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, class := range sortedClasses(data) {
+		adults := make([]AdultClassAssignment, len(class.Adults))
+		copy(adults, class.Adults)
+		sortAdultsByName(adults)
+
+		names := make([]string, 0, len(adults))
+		emails := make([]string, 0, len(adults))
+		for _, adult := range adults {
+			names = append(names, adult.FullName)
+			emails = append(emails, adult.Email)
+		}
+
+		students := make([]FinalAssignment, len(class.Students))
+		copy(students, class.Students)
+		sortStudents(students, classListOrder)
+
+		for _, student := range students {
+			err := writer.Write([]string{
+				class.Catalog.ID,
+				strconv.Itoa(class.Catalog.Session),
+				class.Catalog.Name,
+				class.Catalog.InterestArea,
+				strconv.Itoa(class.Catalog.GradeMin),
+				strconv.Itoa(class.Catalog.GradeMax),
+				strconv.Itoa(class.Catalog.StudentCapacity),
+				class.Catalog.Location,
+				class.Catalog.MeetLocation,
+				student.StudentFullName,
+				strconv.Itoa(student.StudentGrade),
+				student.StudentTeacher,
+				student.StudentStream,
+				student.StudentInterest,
+				strings.Join(names, "; "),
+				strings.Join(emails, "; "),
+				strings.Join(class.Catalog.MeetingDates, ";"),
+				class.Catalog.StartTime,
+				class.Catalog.EndTime,
+				strconv.Itoa(class.Catalog.StudentCapacityMin),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Students outside the catalog only have the class details from their assignment
+	for _, student := range unassigned.Students {
+		err := writer.Write([]string{
+			student.ClassID,
+			strconv.Itoa(student.ClassSession),
+			student.ClassName,
+			"", "", "", "", "", "",
+			student.StudentFullName,
+			strconv.Itoa(student.StudentGrade),
+			student.StudentTeacher,
+			student.StudentStream,
+			student.StudentInterest,
+			"", "", "", "", "", "",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Write the joined class data as nested JSON, one object per class with its adults and students
+func exportJSON(data map[string]ClassData, unassigned UnassignedData, outputFile string) error {
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	export := ClassExport{
+		Classes:    sortedClasses(data),
+		Unassigned: unassigned,
+	}
+
+	// Use empty lists rather than null so consumers can always iterate
+	for i := range export.Classes {
+		if export.Classes[i].Catalog.MeetingDates == nil {
+			export.Classes[i].Catalog.MeetingDates = []string{}
+		}
+		if export.Classes[i].Adults == nil {
+			export.Classes[i].Adults = []AdultClassAssignment{}
+		}
+		if export.Classes[i].Students == nil {
+			export.Classes[i].Students = []FinalAssignment{}
+		}
+	}
+	if export.Unassigned.Adults == nil {
+		export.Unassigned.Adults = []AdultClassAssignment{}
+	}
+	if export.Unassigned.Students == nil {
+		export.Unassigned.Students = []FinalAssignment{}
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
+
+// Return the classes sorted by class ID
+func sortedClasses(data map[string]ClassData) []ClassData {
+	classIDs := make([]string, 0, len(data))
+	for classID := range data {
+		classIDs = append(classIDs, classID)
+	}
+	sort.Strings(classIDs)
+
+	classes := make([]ClassData, 0, len(classIDs))
+	for _, classID := range classIDs {
+		classes = append(classes, data[classID])
+	}
+	return classes
+}
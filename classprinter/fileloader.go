@@ -2,103 +2,107 @@ package main
 
 import (
 	"encoding/csv"
+	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
-)
+	"strings"
+	"time"
 
-type ClassCatalog struct {
-	ID              string
-	Session         int
-	Name            string
-	InterestArea    string
-	GradeMin        int
-	GradeMax        int
-	StudentCapacity int
-	Location        string
-	MeetLocation    string
-}
+	"github.com/christophergm/miniclasses/model"
+)
 
-type AdultClassAssignment struct {
-	ClassID  string
-	FullName string
-	Email    string
-	Note     string
-}
+// The printer works with the shared model types under the names it has always used
+type (
+	ClassCatalog         = model.Class
+	AdultClassAssignment = model.AdultAssignment
+	FinalAssignment      = model.Assignment
+)
 
-type FinalAssignment struct {
-	ClassName       string
-	ClassSession    int
-	ClassID         string
+// StudentNote holds private notes about a student for class leaders
+type StudentNote struct {
 	StudentFullName string
-	StudentGrade    int
-	StudentTeacher  string
-	StudentStream   string
-	StudentInterest string
+	Photo           string // photo permission, e.g. "No photos"
+	Allergies       string
+	Medical         string
 }
 
-func readClassCatalog(file string) ([]ClassCatalog, error) {
-	f, err := os.Open(file)
+// readClassStartTimes reads class start times keyed by class ID (columns: class_id, start_time)
+func readClassStartTimes(file string) (map[string]string, error) {
+	records, err := readRecords(file, 2)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
 
-	reader := csv.NewReader(f)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, err
+	starts := make(map[string]string)
+	for _, record := range records {
+		starts[record[0]] = strings.TrimSpace(record[1])
 	}
 
-	var catalog []ClassCatalog
-	for _, record := range records[1:] { // Skipping header row
-		session, _ := strconv.Atoi(record[1])
-		gradeMin, _ := strconv.Atoi(record[4])
-		gradeMax, _ := strconv.Atoi(record[5])
-		capacity, _ := strconv.Atoi(record[6])
-
-		catalog = append(catalog, ClassCatalog{
-			ID:              record[0],
-			Session:         session,
-			Name:            record[2],
-			InterestArea:    record[3],
-			GradeMin:        gradeMin,
-			GradeMax:        gradeMax,
-			StudentCapacity: capacity,
-			Location:        record[7],
-			MeetLocation:    record[8],
-		})
-	}
+	return starts, nil
+}
 
-	return catalog, nil
+// applyMeetingDates gives the classes without meeting dates in the catalog the dates of their session.
+// Dates in the catalog take precedence over the dates for the whole session.
+func applyMeetingDates(catalog []ClassCatalog, meetingDates map[int][]string) {
+	for i := range catalog {
+		if len(catalog[i].MeetingDates) == 0 {
+			catalog[i].MeetingDates = slices.Clone(meetingDates[catalog[i].Session])
+		}
+	}
 }
 
-func readAdultAssignments(file string) ([]AdultClassAssignment, error) {
-	f, err := os.Open(file)
+// readMeetingDates reads the meeting dates of each session (columns: session, date) as YYYY-MM-DD strings in date order
+func readMeetingDates(file string) (map[int][]string, error) {
+	records, err := readRecords(file, 2)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
 
-	reader := csv.NewReader(f)
-	records, err := reader.ReadAll()
+	dates := make(map[int][]string)
+	for i, record := range records {
+		session, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid session %q: %w", file, i+2, record[0], err)
+		}
+		date := strings.TrimSpace(record[1])
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid meeting date %q, expected YYYY-MM-DD", file, i+2, date)
+		}
+		dates[session] = append(dates[session], date)
+	}
+
+	for session := range dates {
+		sort.Strings(dates[session])
+	}
+
+	return dates, nil
+}
+
+// readStudentNotes reads private student notes (columns: student_full_name, photo, allergies, medical_notes)
+func readStudentNotes(file string) ([]StudentNote, error) {
+	records, err := readRecords(file, 4)
 	if err != nil {
 		return nil, err
 	}
 
-	var assignments []AdultClassAssignment
-	for _, record := range records[1:] {
-		assignments = append(assignments, AdultClassAssignment{
-			ClassID:  record[0],
-			FullName: record[1],
-			Email:    record[2],
-			Note:     record[3],
+	var notes []StudentNote
+	for _, record := range records {
+		notes = append(notes, StudentNote{
+			StudentFullName: strings.TrimSpace(record[0]),
+			Photo:           strings.TrimSpace(record[1]),
+			Allergies:       strings.TrimSpace(record[2]),
+			Medical:         strings.TrimSpace(record[3]),
 		})
 	}
 
-	return assignments, nil
+	return notes, nil
 }
 
-func readFinalAssignments(file string) ([]FinalAssignment, error) {
+// readRecords reads a CSV file with a header row and returns the rows after the header, checking that
+// every row has at least the given number of columns
+func readRecords(file string, columns int) ([][]string, error) {
 	f, err := os.Open(file)
 	if err != nil {
 		return nil, err
@@ -106,27 +110,19 @@ func readFinalAssignments(file string) ([]FinalAssignment, error) {
 	defer f.Close()
 
 	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, err
 	}
-
-	var assignments []FinalAssignment
-	for _, record := range records[1:] {
-		session, _ := strconv.Atoi(record[1])
-		grade, _ := strconv.Atoi(record[4])
-
-		assignments = append(assignments, FinalAssignment{
-			ClassName:       record[0],
-			ClassSession:    session,
-			ClassID:         record[2],
-			StudentFullName: record[3],
-			StudentGrade:    grade,
-			StudentTeacher:  record[5],
-			StudentStream:   record[6],
-			StudentInterest: record[7],
-		})
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: missing header row", file)
 	}
 
-	return assignments, nil
+	for i, record := range records[1:] {
+		if len(record) < columns {
+			return nil, fmt.Errorf("%s line %d: expected %d columns, got %d", file, i+2, columns, len(record))
+		}
+	}
+	return records[1:], nil
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// AdultClass is one class an adult volunteer is assigned to
+type AdultClass struct {
+	ClassID      string
+	ClassName    string
+	Session      int
+	Location     string
+	MeetLocation string
+	Note         string
+}
+
+// AdultRoster lists an adult volunteer's contact info and all of their classes
+type AdultRoster struct {
+	FullName string
+	Email    string
+	Classes  []AdultClass
+}
+
+// AdultConflict is an adult assigned to more than one class in the same session
+type AdultConflict struct {
+	FullName string
+	Session  int
+	Classes  []AdultClass
+}
+
+// AdultCoverage flags classes that are short on adults and adults that are double booked
+type AdultCoverage struct {
+	NoAdults     []ClassCatalog
+	OneAdult     []ClassCatalog
+	DoubleBooked []AdultConflict
+}
+
+// Generate a markdown roster of adult volunteers followed by a coverage check of the classes
+func generateAdultRoster(data map[string]ClassData, outputFile string) error {
+	tmpl, err := loadTemplate("adult_roster_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Extract and sort class IDs
+	classIDs := make([]string, 0, len(data))
+	for classID := range data {
+		classIDs = append(classIDs, classID)
+	}
+	sort.Strings(classIDs)
+
+	// Group classes by adult, keyed by name since the same adult may be listed with different notes
+	rosterMap := make(map[string]*AdultRoster)
+	var coverage AdultCoverage
+	for _, classID := range classIDs {
+		class := data[classID]
+
+		switch len(class.Adults) {
+		case 0:
+			coverage.NoAdults = append(coverage.NoAdults, class.Catalog)
+		case 1:
+			coverage.OneAdult = append(coverage.OneAdult, class.Catalog)
+		}
+
+		for _, adult := range class.Adults {
+			key := strings.ToLower(strings.TrimSpace(adult.FullName))
+			if _, exists := rosterMap[key]; !exists {
+				rosterMap[key] = &AdultRoster{FullName: adult.FullName}
+			}
+			roster := rosterMap[key]
+			if roster.Email == "" {
+				roster.Email = adult.Email
+			}
+			roster.Classes = append(roster.Classes, AdultClass{
+				ClassID:      class.Catalog.ID,
+				ClassName:    class.Catalog.Name,
+				Session:      class.Catalog.Session,
+				Location:     class.Catalog.Location,
+				MeetLocation: class.Catalog.MeetLocation,
+				Note:         adult.Note,
+			})
+		}
+	}
+
+	rosters := make([]AdultRoster, 0, len(rosterMap))
+	for _, roster := range rosterMap {
+		rosters = append(rosters, *roster)
+	}
+	sort.Slice(rosters, func(i, j int) bool {
+		return strings.ToLower(rosters[i].FullName) < strings.ToLower(rosters[j].FullName)
+	})
+
+	// Flag adults with more than one class in the same session
+	for _, roster := range rosters {
+		sessionClasses := make(map[int][]AdultClass)
+		for _, class := range roster.Classes {
+			sessionClasses[class.Session] = append(sessionClasses[class.Session], class)
+		}
+
+		sessions := make([]int, 0, len(sessionClasses))
+		for session := range sessionClasses {
+			sessions = append(sessions, session)
+		}
+		sort.Ints(sessions)
+
+		for _, session := range sessions {
+			if len(sessionClasses[session]) > 1 {
+				coverage.DoubleBooked = append(coverage.DoubleBooked, AdultConflict{
+					FullName: roster.FullName,
+					Session:  session,
+					Classes:  sessionClasses[session],
+				})
+			}
+		}
+	}
+
+	return tmpl.Execute(f, struct {
+		Adults   []AdultRoster
+		Coverage AdultCoverage
+	}{
+		Adults:   rosters,
+		Coverage: coverage,
+	})
+}
@@ -1,20 +1,34 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"sort"
 	"strings"
-	"text/template"
 )
 
 type ClassData struct {
-	Catalog  ClassCatalog
-	Adults   []AdultClassAssignment
-	Students []FinalAssignment
+	Catalog  ClassCatalog           `json:"class"`
+	Adults   []AdultClassAssignment `json:"adults"`
+	Students []FinalAssignment      `json:"students"`
+
+	// Notes are the private notes of the students in the class, keyed by normalized student name.
+	// They are only printed on the leader copy and never exported.
+	Notes map[string]StudentNote `json:"-"`
+}
+
+// UnassignedData holds the students and adults whose class ID is empty or not found in the catalog
+type UnassignedData struct {
+	Students []FinalAssignment      `json:"students"`
+	Adults   []AdultClassAssignment `json:"adults"`
+
+	// Notes are the private notes of the unassigned students, keyed by normalized student name.
+	Notes map[string]StudentNote `json:"-"`
 }
 
-func joinData(catalog []ClassCatalog, adults []AdultClassAssignment, students []FinalAssignment) map[string]ClassData {
+func joinData(catalog []ClassCatalog, adults []AdultClassAssignment, students []FinalAssignment) (map[string]ClassData, UnassignedData) {
 	classMap := make(map[string]ClassData)
+	var unassigned UnassignedData
 
 	// Build initial map from catalog
 	for _, class := range catalog {
@@ -26,6 +40,8 @@ func joinData(catalog []ClassCatalog, adults []AdultClassAssignment, students []
 		if class, exists := classMap[adult.ClassID]; exists {
 			class.Adults = append(class.Adults, adult)
 			classMap[adult.ClassID] = class
+		} else {
+			unassigned.Adults = append(unassigned.Adults, adult)
 		}
 	}
 
@@ -34,10 +50,12 @@ func joinData(catalog []ClassCatalog, adults []AdultClassAssignment, students []
 		if class, exists := classMap[student.ClassID]; exists {
 			class.Students = append(class.Students, student)
 			classMap[student.ClassID] = class
+		} else {
+			unassigned.Students = append(unassigned.Students, student)
 		}
 	}
 
-	return classMap
+	return classMap, unassigned
 }
 
 // Sort adults alphabetically by full name
@@ -47,18 +65,21 @@ func sortAdultsByName(adults []AdultClassAssignment) {
 	})
 }
 
-// Sort students by grade (ascending) and then by first name (alphabetically)
-func sortStudentsByGradeAndName(students []FinalAssignment) {
-	sort.Slice(students, func(i, j int) bool {
-		if students[i].StudentGrade == students[j].StudentGrade {
-			return strings.ToLower(strings.Split(students[i].StudentFullName, " ")[0]) <
-				strings.ToLower(strings.Split(students[j].StudentFullName, " ")[0])
-		}
-		return students[i].StudentGrade < students[j].StudentGrade
-	})
+// Generate the public class list, which leaves out student notes
+func generateMarkdown(data map[string]ClassData, unassigned UnassignedData, outputFile string) error {
+	return renderClassList(data, unassigned, "class_list_template.md", "unassigned_template.md", outputFile)
+}
+
+// Generate the restricted class list for class leaders, which includes student notes
+func generateLeaderMarkdown(data map[string]ClassData, unassigned UnassignedData, outputFile string) error {
+	return renderClassList(data, unassigned, "class_list_leader_template.md", "unassigned_leader_template.md", outputFile)
 }
-func generateMarkdown(data map[string]ClassData, outputFile string) error {
-	tmpl, err := template.ParseFiles("class_list_template.md")
+
+// classDetailsPartial defines the blocks shared by the public and leader class lists
+const classDetailsPartial = "class_details_template.md"
+
+func renderClassList(data map[string]ClassData, unassigned UnassignedData, classTemplate string, unassignedTemplate string, outputFile string) error {
+	tmpl, err := loadTemplate(classTemplate, classDetailsPartial)
 	if err != nil {
 		return err
 	}
@@ -76,6 +97,40 @@ func generateMarkdown(data map[string]ClassData, outputFile string) error {
 	}
 	sort.Strings(classIDs) // Sort classIDs alphabetically
 
+	// Render the capacity and eligibility summary ahead of the classes
+	summaryTmpl, err := loadTemplate("class_summary_template.md")
+	if err != nil {
+		return err
+	}
+	classes := make([]ClassData, 0, len(classIDs))
+	for _, classID := range classIDs {
+		classes = append(classes, data[classID])
+	}
+	err = summaryTmpl.Execute(f, classes)
+	if err != nil {
+		return err
+	}
+
+	// Render the stream balance of every class after the summary
+	balanceTmpl, err := loadTemplate("stream_balance_template.md")
+	if err != nil {
+		return err
+	}
+	err = balanceTmpl.Execute(f, struct {
+		Streams         []string
+		Classes         []ClassData
+		SkewPercent     int
+		SkewMinStudents int
+	}{
+		Streams:         allStreams(classes),
+		Classes:         classes,
+		SkewPercent:     streamSkewPercent,
+		SkewMinStudents: streamSkewMinStudents,
+	})
+	if err != nil {
+		return err
+	}
+
 	// Render the classes in sorted order
 	for _, classID := range classIDs {
 		class := data[classID]
@@ -83,8 +138,8 @@ func generateMarkdown(data map[string]ClassData, outputFile string) error {
 		// Sort adults by name
 		sortAdultsByName(class.Adults)
 
-		// Sort students by grade, then first name
-		sortStudentsByGradeAndName(class.Students)
+		// Sort students by grade, then first name unless another order was given
+		sortStudents(class.Students, classListOrder)
 
 		err := tmpl.Execute(f, class)
 		if err != nil {
@@ -92,5 +147,62 @@ func generateMarkdown(data map[string]ClassData, outputFile string) error {
 		}
 	}
 
+	// Render anyone who did not land in a catalog class at the end so they are not lost
+	if len(unassigned.Students) > 0 || len(unassigned.Adults) > 0 {
+		unassignedTmpl, err := loadTemplate(unassignedTemplate, classDetailsPartial)
+		if err != nil {
+			return err
+		}
+
+		sortAdultsByName(unassigned.Adults)
+		sortStudents(unassigned.Students, classListOrder)
+
+		err = unassignedTmpl.Execute(f, unassigned)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Write one line per student or adult that could not be placed in a catalog class
+func generateWarnings(unassigned UnassignedData, outputFile string) error {
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, student := range unassigned.Students {
+		_, err := fmt.Fprintf(f, "student %s (grade %d, %s) %s\n",
+			student.StudentFullName, student.StudentGrade, student.StudentTeacher, unknownClassReason(student.ClassID, student.ClassName))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, adult := range unassigned.Adults {
+		_, err := fmt.Fprintf(f, "adult %s (%s) %s\n",
+			adult.FullName, adult.Email, unknownClassReason(adult.ClassID, ""))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// Describe why a class ID could not be matched to the catalog
+func unknownClassReason(classID string, className string) string {
+	if classID == "" {
+		if className != "" {
+			return fmt.Sprintf("has no class id (class %q)", className)
+		}
+		return "has no class id"
+	}
+	if className != "" {
+		return fmt.Sprintf("is assigned to unknown class id %s (class %q)", classID, className)
+	}
+	return fmt.Sprintf("is assigned to unknown class id %s", classID)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"sort"
+)
+
+// TeacherCards holds the schedule cards for the students of one teacher
+type TeacherCards struct {
+	Teacher string
+	Cards   []StudentInfo
+}
+
+// Generate an HTML sheet of printable cards, one per student, grouped by teacher so each
+// classroom's cards start on a new page
+func generateStudentCards(data map[string]ClassData, outputFile string) error {
+	tmpl, err := loadHTMLTemplate("student_cards_template.html")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	teacherMap := make(map[string][]StudentInfo)
+	for _, class := range data {
+		for _, student := range class.Students {
+			teacherMap[student.StudentTeacher] = append(teacherMap[student.StudentTeacher], StudentInfo{
+				FinalAssignment:   student,
+				ClassName:         class.Catalog.Name,
+				ClassLocation:     class.Catalog.Location,
+				ClassMeetLocation: class.Catalog.MeetLocation,
+			})
+		}
+	}
+
+	// Extract and sort teacher names
+	teacherNames := make([]string, 0, len(teacherMap))
+	for teacher := range teacherMap {
+		teacherNames = append(teacherNames, teacher)
+	}
+	sort.Strings(teacherNames)
+
+	teachers := make([]TeacherCards, 0, len(teacherNames))
+	for _, teacher := range teacherNames {
+		cards := teacherMap[teacher]
+		// Sort cards by student name within each teacher unless another order was given
+		sortStudentInfos(cards, teacherListOrder)
+		teachers = append(teachers, TeacherCards{Teacher: teacher, Cards: cards})
+	}
+
+	return tmpl.Execute(f, teachers)
+}
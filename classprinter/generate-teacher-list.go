@@ -3,8 +3,6 @@ package main
 import (
 	"os"
 	"sort"
-	"strings"
-	"text/template"
 )
 
 // StudentInfo includes student details along with their class name and location
@@ -25,7 +23,7 @@ type TeacherClassGroup struct {
 
 // Generate a markdown file grouping students by their teacher, and within each teacher, group students by class
 func generateMarkdownByTeacher(data map[string]ClassData, outputFile string) error {
-	tmpl, err := template.ParseFiles("teacher_list_template.md")
+	tmpl, err := loadTemplate("teacher_list_template.md")
 	if err != nil {
 		return err
 	}
@@ -36,34 +34,7 @@ func generateMarkdownByTeacher(data map[string]ClassData, outputFile string) err
 	}
 	defer f.Close()
 
-	// Create a map to group classes by teacher, including class details and students
-	teacherMap := make(map[string]map[string]*TeacherClassGroup)
-
-	for _, class := range data {
-		for _, student := range class.Students {
-			teacher := student.StudentTeacher
-			if teacherMap[teacher] == nil {
-				teacherMap[teacher] = make(map[string]*TeacherClassGroup)
-			}
-
-			if _, exists := teacherMap[teacher][class.Catalog.ID]; !exists {
-				teacherMap[teacher][class.Catalog.ID] = &TeacherClassGroup{
-					ClassName:         class.Catalog.Name,
-					ClassLocation:     class.Catalog.Location,
-					ClassMeetLocation: class.Catalog.MeetLocation,
-					Students:          []StudentInfo{},
-				}
-			}
-
-			studentInfo := StudentInfo{
-				FinalAssignment:   student,
-				ClassName:         class.Catalog.Name,
-				ClassLocation:     class.Catalog.Location,
-				ClassMeetLocation: class.Catalog.MeetLocation,
-			}
-			teacherMap[teacher][class.Catalog.ID].Students = append(teacherMap[teacher][class.Catalog.ID].Students, studentInfo)
-		}
-	}
+	teacherMap := groupByTeacher(data)
 
 	// Extract and sort teacher names
 	teacherNames := make([]string, 0, len(teacherMap))
@@ -86,10 +57,8 @@ func generateMarkdownByTeacher(data map[string]ClassData, outputFile string) err
 		classes := make([]TeacherClassGroup, 0, len(classIDs))
 		for _, classID := range classIDs {
 			classGroup := classMap[classID]
-			// Sort students by name within each class
-			sort.Slice(classGroup.Students, func(i, j int) bool {
-				return strings.ToLower(classGroup.Students[i].StudentFullName) < strings.ToLower(classGroup.Students[j].StudentFullName)
-			})
+			// Sort students by name within each class unless another order was given
+			sortStudentInfos(classGroup.Students, teacherListOrder)
 			classes = append(classes, *classGroup)
 		}
 
@@ -108,3 +77,36 @@ func generateMarkdownByTeacher(data map[string]ClassData, outputFile string) err
 
 	return nil
 }
+
+// Group classes by teacher, keyed by teacher name and then class ID, including class details and students
+func groupByTeacher(data map[string]ClassData) map[string]map[string]*TeacherClassGroup {
+	teacherMap := make(map[string]map[string]*TeacherClassGroup)
+
+	for _, class := range data {
+		for _, student := range class.Students {
+			teacher := student.StudentTeacher
+			if teacherMap[teacher] == nil {
+				teacherMap[teacher] = make(map[string]*TeacherClassGroup)
+			}
+
+			if _, exists := teacherMap[teacher][class.Catalog.ID]; !exists {
+				teacherMap[teacher][class.Catalog.ID] = &TeacherClassGroup{
+					ClassName:         class.Catalog.Name,
+					ClassLocation:     class.Catalog.Location,
+					ClassMeetLocation: class.Catalog.MeetLocation,
+					Students:          []StudentInfo{},
+				}
+			}
+
+			studentInfo := StudentInfo{
+				FinalAssignment:   student,
+				ClassName:         class.Catalog.Name,
+				ClassLocation:     class.Catalog.Location,
+				ClassMeetLocation: class.Catalog.MeetLocation,
+			}
+			teacherMap[teacher][class.Catalog.ID].Students = append(teacherMap[teacher][class.Catalog.ID].Students, studentInfo)
+		}
+	}
+
+	return teacherMap
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// ClassSchedule configures when classes start and how early students are sent from their homeroom
+type ClassSchedule struct {
+	DefaultStart string            // start time used when a class has no entry in Starts, e.g. "14:30"
+	Starts       map[string]string // start time per class ID
+	LeaveBefore  time.Duration     // how long before the class starts students leave their homeroom
+}
+
+// DismissalGroup is the set of a teacher's students who leave at the same time for the same destination
+type DismissalGroup struct {
+	LeaveTime   string
+	Destination string
+	Classes     []DismissalClass
+}
+
+// DismissalClass is a class on a teacher's walk sheet along with its start time
+type DismissalClass struct {
+	TeacherClassGroup
+	StartTime string
+}
+
+// Generate a markdown walk sheet for each teacher listing which students leave at what time,
+// grouped by where they meet their class
+func generateTeacherLogistics(data map[string]ClassData, schedule ClassSchedule, outputFile string) error {
+	tmpl, err := loadTemplate("teacher_logistics_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	teacherMap := groupByTeacher(data)
+
+	// Extract and sort teacher names
+	teacherNames := make([]string, 0, len(teacherMap))
+	for teacher := range teacherMap {
+		teacherNames = append(teacherNames, teacher)
+	}
+	sort.Strings(teacherNames)
+
+	for _, teacher := range teacherNames {
+		type groupKey struct {
+			leave       time.Time
+			destination string
+		}
+		groupMap := make(map[groupKey]*DismissalGroup)
+
+		for classID, classGroup := range teacherMap[teacher] {
+			start, err := schedule.startTime(classID)
+			if err != nil {
+				return err
+			}
+			leave := start.Add(-schedule.LeaveBefore)
+
+			destination := classGroup.ClassMeetLocation
+			if destination == "" {
+				destination = classGroup.ClassLocation
+			}
+
+			key := groupKey{leave: leave, destination: destination}
+			if _, exists := groupMap[key]; !exists {
+				groupMap[key] = &DismissalGroup{
+					LeaveTime:   leave.Format(time.Kitchen),
+					Destination: destination,
+				}
+			}
+
+			// Sort students by name within each class unless another order was given
+			sortStudentInfos(classGroup.Students, teacherListOrder)
+			groupMap[key].Classes = append(groupMap[key].Classes, DismissalClass{
+				TeacherClassGroup: *classGroup,
+				StartTime:         start.Format(time.Kitchen),
+			})
+		}
+
+		// Sort groups by leave time, then destination
+		keys := make([]groupKey, 0, len(groupMap))
+		for key := range groupMap {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].leave.Equal(keys[j].leave) {
+				return keys[i].destination < keys[j].destination
+			}
+			return keys[i].leave.Before(keys[j].leave)
+		})
+
+		groups := make([]DismissalGroup, 0, len(keys))
+		for _, key := range keys {
+			group := groupMap[key]
+			sort.Slice(group.Classes, func(i, j int) bool {
+				return group.Classes[i].ClassName < group.Classes[j].ClassName
+			})
+			groups = append(groups, *group)
+		}
+
+		err := tmpl.Execute(f, struct {
+			Teacher string
+			Groups  []DismissalGroup
+		}{
+			Teacher: teacher,
+			Groups:  groups,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startTime returns the configured start time for the class, falling back to the default start
+func (s ClassSchedule) startTime(classID string) (time.Time, error) {
+	value, exists := s.Starts[classID]
+	if !exists || value == "" {
+		value = s.DefaultStart
+	}
+
+	start, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q for class %s, expected HH:MM", value, classID)
+	}
+	return start, nil
+}
@@ -1,3 +1,11 @@
 module github.com/christophergm/miniclasses/classprinter
 
 go 1.23.0
+
+require github.com/christophergm/miniclasses/model v0.0.0
+
+replace github.com/christophergm/miniclasses/model => ../model
+
+require github.com/christophergm/miniclasses/assign v0.0.0
+
+replace github.com/christophergm/miniclasses/assign => ../assign
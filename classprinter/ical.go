@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultClassLength is used for the end of a meeting when the catalog has a start time but no end time
+const defaultClassLength = time.Hour
+
+// Generate an iCalendar file for every class and every student with one event per meeting date
+func generateCalendars(data map[string]ClassData, outputDir string) error {
+	err := os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	studentEvents := make(map[string][]string)
+
+	for _, class := range sortedClasses(data) {
+		if len(class.Catalog.MeetingDates) == 0 {
+			continue
+		}
+
+		events, err := classEvents(class, "", stamp)
+		if err != nil {
+			return err
+		}
+		err = writeCalendar(filepath.Join(outputDir, "class-"+slug(class.Catalog.ID)+".ics"), class.Catalog.Name, events)
+		if err != nil {
+			return err
+		}
+
+		for _, student := range class.Students {
+			events, err := classEvents(class, student.StudentFullName, stamp)
+			if err != nil {
+				return err
+			}
+			studentEvents[student.StudentFullName] = append(studentEvents[student.StudentFullName], events...)
+		}
+	}
+
+	names := make([]string, 0, len(studentEvents))
+	for name := range studentEvents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		err := writeCalendar(filepath.Join(outputDir, "student-"+slug(name)+".ics"), name+" Mini Classes", studentEvents[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Build one VEVENT per meeting date of the class. The student name, if given, makes the event UIDs unique per student.
+func classEvents(class ClassData, student string, stamp string) ([]string, error) {
+	catalog := class.Catalog
+
+	var description []string
+	if catalog.MeetLocation != "" {
+		description = append(description, "Meet at: "+catalog.MeetLocation)
+	}
+	if len(class.Adults) > 0 {
+		adults := make([]string, 0, len(class.Adults))
+		for _, adult := range class.Adults {
+			adults = append(adults, adult.FullName)
+		}
+		description = append(description, "Adults: "+strings.Join(adults, ", "))
+	}
+
+	var events []string
+	for _, date := range catalog.MeetingDates {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("class %s: invalid meeting date %q", catalog.ID, date)
+		}
+
+		uid := catalog.ID + "-" + day.Format("20060102")
+		if student != "" {
+			uid += "-" + slug(student)
+		}
+
+		lines := []string{
+			"BEGIN:VEVENT",
+			"UID:" + uid + "@miniclasses",
+			"DTSTAMP:" + stamp,
+		}
+
+		if catalog.StartTime == "" {
+			// Without a start time the meeting is an all-day event
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+day.Format("20060102"),
+				"DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			start, err := meetingTime(day, catalog.StartTime)
+			if err != nil {
+				return nil, fmt.Errorf("class %s: %w", catalog.ID, err)
+			}
+			end := start.Add(defaultClassLength)
+			if catalog.EndTime != "" {
+				end, err = meetingTime(day, catalog.EndTime)
+				if err != nil {
+					return nil, fmt.Errorf("class %s: %w", catalog.ID, err)
+				}
+			}
+			// Floating local times so the calendar shows the meeting in the school's time zone
+			lines = append(lines,
+				"DTSTART:"+start.Format("20060102T150405"),
+				"DTEND:"+end.Format("20060102T150405"))
+		}
+
+		lines = append(lines, "SUMMARY:"+escapeText("Mini Class: "+catalog.Name))
+		if catalog.Location != "" {
+			lines = append(lines, "LOCATION:"+escapeText(catalog.Location))
+		}
+		if len(description) > 0 {
+			lines = append(lines, "DESCRIPTION:"+escapeText(strings.Join(description, "\n")))
+		}
+		lines = append(lines, "END:VEVENT")
+
+		events = append(events, strings.Join(lines, "\r\n"))
+	}
+
+	return events, nil
+}
+
+// Combine the meeting date with an HH:MM time of day
+func meetingTime(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), nil
+}
+
+// Write a VCALENDAR wrapping the events, folding long lines as required by RFC 5545
+func writeCalendar(file string, name string, events []string) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//OPTO//Mini Classes//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escapeText(name),
+	}
+	for _, event := range events {
+		lines = append(lines, strings.Split(event, "\r\n")...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldLine(line))
+		b.WriteString("\r\n")
+	}
+
+	return os.WriteFile(file, []byte(b.String()), 0644)
+}
+
+// Escape commas, semicolons, backslashes and newlines in a TEXT value
+func escapeText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// Fold lines longer than 75 octets onto continuation lines starting with a space
+func foldLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
+
+// Turn a name into a file name friendly slug, e.g. "Lexie Monahan" -> "lexie-monahan"
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// hasMeetingDates reports whether any class in the catalog lists its meeting dates
+func hasMeetingDates(catalog []ClassCatalog) bool {
+	for _, class := range catalog {
+		if len(class.MeetingDates) > 0 {
+			return true
+		}
+	}
+	return false
+}
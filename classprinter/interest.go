@@ -0,0 +1,111 @@
+package main
+
+import "os"
+
+// InterestCounts tallies how interested students were in the class they were assigned
+type InterestCounts struct {
+	VeryInterested int
+	Interested     int
+	NotInterested  int
+	Unknown        int
+}
+
+// Total is the number of students counted
+func (c InterestCounts) Total() int {
+	return c.VeryInterested + c.Interested + c.NotInterested + c.Unknown
+}
+
+// SatisfiedPercent is the share of students who were at least interested in their class
+func (c InterestCounts) SatisfiedPercent() int {
+	if c.Total() == 0 {
+		return 0
+	}
+	return (c.VeryInterested + c.Interested) * 100 / c.Total()
+}
+
+func (c *InterestCounts) add(student FinalAssignment) {
+	switch interestRank(student.StudentInterest) {
+	case interestVery:
+		c.VeryInterested++
+	case interestMaybe:
+		c.Interested++
+	case interestNope:
+		c.NotInterested++
+	default:
+		c.Unknown++
+	}
+}
+
+// InterestCounts tallies the interest of the students in the class
+func (c ClassData) InterestCounts() InterestCounts {
+	var counts InterestCounts
+	for _, student := range c.Students {
+		counts.add(student)
+	}
+	return counts
+}
+
+// ClassInterest is one row of the interest summary
+type ClassInterest struct {
+	ClassID   string
+	ClassName string
+	Counts    InterestCounts
+}
+
+// Generate a markdown summary of how interested students were in their assigned classes, per class and
+// overall, listing the students who got a class they were not at all interested in
+func generateInterestSummary(data map[string]ClassData, unassigned UnassignedData, outputFile string) error {
+	tmpl, err := loadTemplate("interest_summary_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var classes []ClassInterest
+	var overall InterestCounts
+	var notInterested []FinalAssignment
+
+	for _, class := range sortedClasses(data) {
+		classes = append(classes, ClassInterest{
+			ClassID:   class.Catalog.ID,
+			ClassName: class.Catalog.Name,
+			Counts:    class.InterestCounts(),
+		})
+		for _, student := range class.Students {
+			overall.add(student)
+			if interestRank(student.StudentInterest) == interestNope {
+				notInterested = append(notInterested, student)
+			}
+		}
+	}
+
+	// Students outside the catalog, such as the Fallback class, count toward the overall totals too
+	if len(unassigned.Students) > 0 {
+		row := ClassInterest{ClassName: "Unassigned / unknown class"}
+		for _, student := range unassigned.Students {
+			row.Counts.add(student)
+			overall.add(student)
+			if interestRank(student.StudentInterest) == interestNope {
+				notInterested = append(notInterested, student)
+			}
+		}
+		classes = append(classes, row)
+	}
+
+	sortStudents(notInterested, teacherListOrder)
+
+	return tmpl.Execute(f, struct {
+		Classes       []ClassInterest
+		Overall       InterestCounts
+		NotInterested []FinalAssignment
+	}{
+		Classes:       classes,
+		Overall:       overall,
+		NotInterested: notInterested,
+	})
+}
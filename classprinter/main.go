@@ -1,38 +1,372 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/christophergm/miniclasses/assign"
+	"github.com/christophergm/miniclasses/model"
 )
 
 func main() {
-	catalog, err := readClassCatalog("../files/class_catalog.csv")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "attendance":
+			runAttendance(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "score":
+			runScore(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		}
+	}
+
+	flag.StringVar(&templateDir, "templates", "", "directory of templates that override the embedded defaults")
+	session := flag.Int("session", 0, "only print classes in this session (0 prints every session)")
+	combined := flag.Bool("combined", false, "also print each student's schedule across all sessions of the term")
+	startTime := flag.String("start-time", "14:00", "class start time (HH:MM) used on the teacher logistics sheet")
+	startTimesFile := flag.String("start-times", "", "optional CSV of class_id,start_time overriding --start-time per class")
+	leaveBefore := flag.Duration("leave-before", 5*time.Minute, "how long before class starts students leave their homeroom")
+	sortSpec := flag.String("sort", "", "student sort order for the rosters, e.g. \"grade,last,first\" (keys: grade, first, last, name, teacher, stream, interest; prefix - to reverse)")
+	notesFile := flag.String("notes", "", "optional CSV of student_full_name,photo,allergies,medical_notes printed only on the leader copy of the class list")
+	meetingDatesFile := flag.String("meeting-dates", "", "optional CSV of session,date giving the meeting dates of the classes that list none in the catalog, used for attendance sheets, calendars and exports")
+	runAssign := flag.Bool("assign", false, "assign students from the files in ../files before printing, writing ../output/final_assignments.csv")
+	assignMode := flag.String("assign-mode", "greedy", "assignment algorithm used by --assign: greedy or optimal")
+	assignWeights := flag.String("weights", "", "weights used by --assign to rearrange students for a better score, e.g. \"interest=1,stream=0.25,grade=0.1,friends=0.5,disappointment=0.5\" (empty skips the rearranging)")
+	runs := flag.Int("runs", 1, "number of randomized runs --assign tries concurrently, keeping the best scoring one and writing ../output/assignment_runs.md")
+	cancel := flag.String("cancel", "", "comma separated IDs of classes --assign leaves out, e.g. those suggested in ../output/cancellations.md")
+	seed := flag.Uint64("seed", 0, "seed used by --assign to repeat an earlier run exactly (0 picks a random seed); the seed of every run is recorded in ../output/final_assignments_run.csv")
+	flag.Parse()
+
+	if *sortSpec != "" {
+		order, err := parseSortSpec(*sortSpec)
+		if err != nil {
+			log.Fatalf("Error parsing sort order: %v", err)
+		}
+		studentOrder = order
+	}
+
+	// The student list gives the first and last sort keys the real name columns; without it full names are split
+	if _, err := os.Stat("../files/student_list.csv"); err == nil {
+		studentNames, err = readStudentNames("../files/student_list.csv")
+		if err != nil {
+			log.Fatalf("Error reading student list: %v", err)
+		}
+	}
+
+	if *runAssign {
+		assignSession := *session
+		if assignSession == 0 {
+			assignSession = 1
+		}
+		mode, err := assign.ParseMode(*assignMode)
+		if err != nil {
+			log.Fatalf("Error assigning students: %v", err)
+		}
+		var weights *assign.Weights
+		if *assignWeights != "" {
+			parsed, err := assign.ParseWeights(*assignWeights)
+			if err != nil {
+				log.Fatalf("Error assigning students: %v", err)
+			}
+			weights = &parsed
+		}
+		options := assignOptions{Mode: mode, Weights: weights, Seed: *seed, Runs: *runs}
+		for _, id := range strings.Split(*cancel, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				options.Cancel = append(options.Cancel, id)
+			}
+		}
+		err = assignStudents("../files", assignSession, options, "../output/final_assignments.csv", "../output/assignment_runs.md", "../output/cancellations.md")
+		if err != nil {
+			log.Fatalf("Error assigning students: %v", err)
+		}
+		fmt.Println("Students assigned successfully.")
+	}
+
+	catalog, err := model.ReadClasses("../files/class_catalog.csv")
 	if err != nil {
 		log.Fatalf("Error reading class catalog: %v", err)
 	}
 
-	adults, err := readAdultAssignments("../files/adult_class_assignments.csv")
+	// Session dates from the --meeting-dates file fill in the classes without their own dates, so the
+	// attendance sheets, calendars and exports all show the same meetings
+	if *meetingDatesFile != "" {
+		meetingDates, err := readMeetingDates(*meetingDatesFile)
+		if err != nil {
+			log.Fatalf("Error reading meeting dates: %v", err)
+		}
+		applyMeetingDates(catalog, meetingDates)
+	}
+
+	adults, err := model.ReadAdultAssignments("../files/adult_class_assignments.csv")
 	if err != nil {
 		log.Fatalf("Error reading adult assignments: %v", err)
 	}
 
-	students, err := readFinalAssignments("../output/final_assignments.csv")
+	students, err := model.ReadAssignments("../output/final_assignments.csv")
 	if err != nil {
 		log.Fatalf("Error reading final assignments: %v", err)
 	}
 
-	classData := joinData(catalog, adults, students)
+	if *combined {
+		termData, termUnassigned := joinData(catalog, adults, students)
+		err = generateStudentSchedules(termData, termUnassigned, "../output/student_schedules.md")
+		if err != nil {
+			log.Fatalf("Error generating student schedules: %v", err)
+		}
+		fmt.Println("Student schedules generated successfully.")
+	}
 
-	err = generateMarkdown(classData, "../output/class_list.md")
+	if *session > 0 {
+		catalog, adults, students = filterSession(catalog, adults, students, *session)
+	}
+
+	classData, unassigned := joinData(catalog, adults, students)
+
+	err = generateMarkdown(classData, unassigned, "../output/class_list.md")
 	if err != nil {
 		log.Fatalf("Error generating class list: %v", err)
 	}
 	fmt.Println("Class list generated successfully.")
 
+	if *notesFile != "" {
+		notes, err := readStudentNotes(*notesFile)
+		if err != nil {
+			log.Fatalf("Error reading student notes: %v", err)
+		}
+		for _, name := range attachNotes(classData, &unassigned, notes) {
+			fmt.Printf("Warning: note for %s does not match a student\n", name)
+		}
+		err = generateLeaderMarkdown(classData, unassigned, "../output/class_list_leader.md")
+		if err != nil {
+			log.Fatalf("Error generating leader class list: %v", err)
+		}
+		fmt.Println("Leader class list generated successfully.")
+	}
+
 	err = generateMarkdownByTeacher(classData, "../output/teacher_list.md")
 	if err != nil {
 		log.Fatalf("Error generating class list: %v", err)
 	}
 	fmt.Println("Teacher list generated successfully.")
 
+	// Start times from the --start-times file take precedence over the catalog
+	schedule := ClassSchedule{DefaultStart: *startTime, Starts: make(map[string]string), LeaveBefore: *leaveBefore}
+	for _, class := range catalog {
+		if class.StartTime != "" {
+			schedule.Starts[class.ID] = class.StartTime
+		}
+	}
+	if *startTimesFile != "" {
+		starts, err := readClassStartTimes(*startTimesFile)
+		if err != nil {
+			log.Fatalf("Error reading class start times: %v", err)
+		}
+		for classID, start := range starts {
+			schedule.Starts[classID] = start
+		}
+	}
+	err = generateTeacherLogistics(classData, schedule, "../output/teacher_logistics.md")
+	if err != nil {
+		log.Fatalf("Error generating teacher logistics: %v", err)
+	}
+	fmt.Println("Teacher logistics generated successfully.")
+
+	err = generateAdultRoster(classData, "../output/adult_roster.md")
+	if err != nil {
+		log.Fatalf("Error generating adult roster: %v", err)
+	}
+	fmt.Println("Adult roster generated successfully.")
+
+	if hasMeetingDates(catalog) {
+		err = generateAttendanceSheets(classData, "../output/attendance_sheets.md", "../output/attendance.csv")
+		if err != nil {
+			log.Fatalf("Error generating attendance sheets: %v", err)
+		}
+		fmt.Println("Attendance sheets generated successfully.")
+	}
+
+	if hasMeetingDates(catalog) {
+		err = generateCalendars(classData, "../output/calendars")
+		if err != nil {
+			log.Fatalf("Error generating calendars: %v", err)
+		}
+		fmt.Println("Calendars generated successfully.")
+	}
+
+	err = generateInterestSummary(classData, unassigned, "../output/interest_summary.md")
+	if err != nil {
+		log.Fatalf("Error generating interest summary: %v", err)
+	}
+	fmt.Println("Interest summary generated successfully.")
+
+	err = exportCSV(classData, unassigned, "../output/class_data.csv")
+	if err != nil {
+		log.Fatalf("Error exporting CSV: %v", err)
+	}
+	err = exportJSON(classData, unassigned, "../output/class_data.json")
+	if err != nil {
+		log.Fatalf("Error exporting JSON: %v", err)
+	}
+	fmt.Println("Class data exported successfully.")
+
+	err = generateStudentCards(classData, "../output/student_cards.html")
+	if err != nil {
+		log.Fatalf("Error generating student cards: %v", err)
+	}
+	fmt.Println("Student cards generated successfully.")
+
+	err = generateWarnings(unassigned, "../output/warnings.txt")
+	if err != nil {
+		log.Fatalf("Error generating warnings: %v", err)
+	}
+	if len(unassigned.Students) > 0 || len(unassigned.Adults) > 0 {
+		fmt.Printf("Warning: %d students and %d adults are not in a catalog class, see ../output/warnings.txt\n",
+			len(unassigned.Students), len(unassigned.Adults))
+	}
+
+}
+
+// runAttendance totals a filled-in attendance CSV, e.g. classprinter attendance ../output/attendance.csv
+func runAttendance(args []string) {
+	flags := flag.NewFlagSet("attendance", flag.ExitOnError)
+	flags.StringVar(&templateDir, "templates", "", "directory of templates that override the embedded defaults")
+	out := flags.String("out", "../output/attendance_report.md", "path to write the attendance report to")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		log.Fatalf("Usage: classprinter attendance [--out report.md] attendance.csv")
+	}
+
+	attendance, err := readAttendance(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Error reading attendance: %v", err)
+	}
+
+	err = generateAttendanceReport(totalAttendance(attendance), *out)
+	if err != nil {
+		log.Fatalf("Error generating attendance report: %v", err)
+	}
+	fmt.Println("Attendance report generated successfully.")
+}
+
+// runDiff reports who was added, removed or moved between two assignment runs,
+// e.g. classprinter diff ../output/final_assignments_old.csv ../output/final_assignments.csv
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.StringVar(&templateDir, "templates", "", "directory of templates that override the embedded defaults")
+	out := flags.String("out", "../output/assignment_diff.md", "path to write the report to, as HTML if it ends in .html")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		log.Fatalf("Usage: classprinter diff [--out report.md|report.html] old_assignments.csv new_assignments.csv")
+	}
+
+	oldAssignments, err := model.ReadAssignments(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Error reading old assignments: %v", err)
+	}
+	newAssignments, err := model.ReadAssignments(flags.Arg(1))
+	if err != nil {
+		log.Fatalf("Error reading new assignments: %v", err)
+	}
+
+	diff := groupChanges(diffAssignments(oldAssignments, newAssignments))
+	diff.OldFile = flags.Arg(0)
+	diff.NewFile = flags.Arg(1)
+	diff.OldRun, err = runSummary(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Error reading old run settings: %v", err)
+	}
+	diff.NewRun, err = runSummary(flags.Arg(1))
+	if err != nil {
+		log.Fatalf("Error reading new run settings: %v", err)
+	}
+
+	err = generateDiffReport(diff, *out)
+	if err != nil {
+		log.Fatalf("Error generating assignment diff: %v", err)
+	}
+	fmt.Printf("Assignment diff generated successfully: %d added, %d removed, %d moved.\n", diff.Added, diff.Removed, diff.Moved)
+}
+
+// runHistory saves final assignments in the history used to keep later sessions fair,
+// e.g. classprinter history ../output/final_assignments.csv
+func runHistory(args []string) {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	dir := flags.String("dir", "../files/history", "history directory read by --assign")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		log.Fatalf("Usage: classprinter history [--dir history] assignments.csv")
+	}
+
+	assignments, err := model.ReadAssignments(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Error reading assignments: %v", err)
+	}
+
+	sessions, err := assign.SaveHistory(*dir, assignments)
+	if err != nil {
+		log.Fatalf("Error saving history: %v", err)
+	}
+	fmt.Printf("History saved successfully for sessions %v.\n", sessions)
+}
+
+// runScore prints the weighted score of an assignment with a breakdown by goal and by class,
+// e.g. classprinter score --pairs ../files/pair_requests.csv ../output/final_assignments.csv
+func runScore(args []string) {
+	flags := flag.NewFlagSet("score", flag.ExitOnError)
+	flags.StringVar(&templateDir, "templates", "", "directory of templates that override the embedded defaults")
+	weightsSpec := flags.String("weights", "", "weights of the score, e.g. \"interest=1,stream=0.25\" (unlisted weights keep their defaults)")
+	pairsFile := flags.String("pairs", "", "optional CSV of student_a,student_b pair requests")
+	previousFile := flags.String("previous", "", "optional final assignments of the previous session, to score last session's disappointed students")
+	out := flags.String("out", "../output/score.md", "path to write the score report to")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		log.Fatalf("Usage: classprinter score [--weights spec] [--pairs pairs.csv] [--previous previous.csv] [--out score.md] assignments.csv")
+	}
+
+	weights, err := assign.ParseWeights(*weightsSpec)
+	if err != nil {
+		log.Fatalf("Error parsing weights: %v", err)
+	}
+	scorer := assign.Scorer{Weights: weights}
+
+	if *pairsFile != "" {
+		scorer.Pairs, err = model.ReadStudentPairs(*pairsFile)
+		if err != nil {
+			log.Fatalf("Error reading pair requests: %v", err)
+		}
+	}
+	if *previousFile != "" {
+		previous, err := model.ReadAssignments(*previousFile)
+		if err != nil {
+			log.Fatalf("Error reading previous assignments: %v", err)
+		}
+		scorer.Disappointed = assign.Disappointed(previous)
+	}
+
+	assignments, err := model.ReadAssignments(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Error reading assignments: %v", err)
+	}
+
+	score := scorer.Score(assignments)
+	err = generateScoreReport(score, flags.Arg(0), *out)
+	if err != nil {
+		log.Fatalf("Error generating score report: %v", err)
+	}
+	fmt.Printf("Score report generated successfully: total %.3f.\n", score.Total)
 }
@@ -0,0 +1,83 @@
+package main
+
+import "strings"
+
+// IsEmpty reports whether the note has nothing to tell a class leader
+func (n StudentNote) IsEmpty() bool {
+	return n.Photo == "" && n.Allergies == "" && n.Medical == ""
+}
+
+// NoteFor returns the note for a student in the class, or an empty note if there is none
+func (c ClassData) NoteFor(studentFullName string) StudentNote {
+	return c.Notes[noteKey(studentFullName)]
+}
+
+// StudentNotes lists the non-empty notes of the class students in roster order
+func (c ClassData) StudentNotes() []StudentNote {
+	return studentNotes(c.Students, c.Notes)
+}
+
+// NoteFor returns the note for an unassigned student, or an empty note if there is none
+func (u UnassignedData) NoteFor(studentFullName string) StudentNote {
+	return u.Notes[noteKey(studentFullName)]
+}
+
+// StudentNotes lists the non-empty notes of the unassigned students in roster order
+func (u UnassignedData) StudentNotes() []StudentNote {
+	return studentNotes(u.Students, u.Notes)
+}
+
+func studentNotes(students []FinalAssignment, noteMap map[string]StudentNote) []StudentNote {
+	var notes []StudentNote
+	for _, student := range students {
+		if note := noteMap[noteKey(student.StudentFullName)]; !note.IsEmpty() {
+			// Use the roster spelling of the name rather than the notes file spelling
+			note.StudentFullName = student.StudentFullName
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// Join the student notes into the classes of the students they belong to, and into the unassigned
+// students. Returns the names of notes that did not match any student.
+func attachNotes(data map[string]ClassData, unassigned *UnassignedData, notes []StudentNote) []string {
+	noteMap := make(map[string]StudentNote)
+	for _, note := range notes {
+		noteMap[noteKey(note.StudentFullName)] = note
+	}
+
+	matched := make(map[string]bool)
+	for classID, class := range data {
+		class.Notes = make(map[string]StudentNote)
+		for _, student := range class.Students {
+			key := noteKey(student.StudentFullName)
+			if note, exists := noteMap[key]; exists {
+				class.Notes[key] = note
+				matched[key] = true
+			}
+		}
+		data[classID] = class
+	}
+
+	unassigned.Notes = make(map[string]StudentNote)
+	for _, student := range unassigned.Students {
+		key := noteKey(student.StudentFullName)
+		if note, exists := noteMap[key]; exists {
+			unassigned.Notes[key] = note
+			matched[key] = true
+		}
+	}
+
+	var unmatched []string
+	for _, note := range notes {
+		if !matched[noteKey(note.StudentFullName)] {
+			unmatched = append(unmatched, note.StudentFullName)
+		}
+	}
+	return unmatched
+}
+
+func noteKey(studentFullName string) string {
+	return strings.ToLower(strings.Join(strings.Fields(studentFullName), " "))
+}
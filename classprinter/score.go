@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/christophergm/miniclasses/assign"
+)
+
+// Generate a markdown breakdown of the weighted score of an assignment, by goal and by class,
+// listing the pair requests and disappointed students that were not met
+func generateScoreReport(score assign.Score, assignmentsFile string, outputFile string) error {
+	tmpl, err := loadTemplate("score_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tmpl.Execute(f, struct {
+		File string
+		assign.Score
+	}{
+		File:  assignmentsFile,
+		Score: score,
+	})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// ScheduleEntry is the class a student attends in one session
+type ScheduleEntry struct {
+	Session           int
+	ClassName         string
+	ClassLocation     string
+	ClassMeetLocation string
+}
+
+// StudentSchedule lists a student's classes across all sessions of the term
+type StudentSchedule struct {
+	StudentFullName string
+	StudentGrade    int
+	StudentTeacher  string
+	Entries         []ScheduleEntry
+}
+
+// Keep only the classes, adults and students that belong to the given session.
+// Adults assigned to a class missing from the catalog are kept so they are still reported as unassigned.
+func filterSession(catalog []ClassCatalog, adults []AdultClassAssignment, students []FinalAssignment, session int) ([]ClassCatalog, []AdultClassAssignment, []FinalAssignment) {
+	classSessions := make(map[string]int)
+	var sessionCatalog []ClassCatalog
+	for _, class := range catalog {
+		classSessions[class.ID] = class.Session
+		if class.Session == session {
+			sessionCatalog = append(sessionCatalog, class)
+		}
+	}
+
+	var sessionAdults []AdultClassAssignment
+	for _, adult := range adults {
+		if classSession, exists := classSessions[adult.ClassID]; !exists || classSession == session {
+			sessionAdults = append(sessionAdults, adult)
+		}
+	}
+
+	var sessionStudents []FinalAssignment
+	for _, student := range students {
+		if student.ClassSession == session {
+			sessionStudents = append(sessionStudents, student)
+		}
+	}
+
+	return sessionCatalog, sessionAdults, sessionStudents
+}
+
+// Generate a markdown file with one schedule per student showing their class in every session of the term
+func generateStudentSchedules(data map[string]ClassData, unassigned UnassignedData, outputFile string) error {
+	tmpl, err := loadTemplate("student_schedule_template.md")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Collect every session offered in the catalog so each schedule has the same rows
+	sessionSet := make(map[int]bool)
+	for _, class := range data {
+		sessionSet[class.Catalog.Session] = true
+	}
+
+	scheduleMap := make(map[string]*StudentSchedule)
+	entries := make(map[string]map[int]ScheduleEntry)
+	addEntry := func(student FinalAssignment, entry ScheduleEntry) {
+		if _, exists := scheduleMap[student.StudentFullName]; !exists {
+			scheduleMap[student.StudentFullName] = &StudentSchedule{
+				StudentFullName: student.StudentFullName,
+				StudentGrade:    student.StudentGrade,
+				StudentTeacher:  student.StudentTeacher,
+			}
+			entries[student.StudentFullName] = make(map[int]ScheduleEntry)
+		}
+		entries[student.StudentFullName][entry.Session] = entry
+		sessionSet[entry.Session] = true
+	}
+
+	for _, class := range data {
+		for _, student := range class.Students {
+			addEntry(student, ScheduleEntry{
+				Session:           class.Catalog.Session,
+				ClassName:         class.Catalog.Name,
+				ClassLocation:     class.Catalog.Location,
+				ClassMeetLocation: class.Catalog.MeetLocation,
+			})
+		}
+	}
+
+	// Students outside the catalog still get a row so their schedule is complete
+	for _, student := range unassigned.Students {
+		addEntry(student, ScheduleEntry{
+			Session:   student.ClassSession,
+			ClassName: student.ClassName,
+		})
+	}
+
+	sessions := make([]int, 0, len(sessionSet))
+	for session := range sessionSet {
+		sessions = append(sessions, session)
+	}
+	sort.Ints(sessions)
+
+	schedules := make([]StudentSchedule, 0, len(scheduleMap))
+	for name, schedule := range scheduleMap {
+		for _, session := range sessions {
+			entry, exists := entries[name][session]
+			if !exists {
+				entry = ScheduleEntry{Session: session}
+			}
+			schedule.Entries = append(schedule.Entries, entry)
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	// Sort students by teacher, then by name
+	sort.Slice(schedules, func(i, j int) bool {
+		if schedules[i].StudentTeacher == schedules[j].StudentTeacher {
+			return strings.ToLower(schedules[i].StudentFullName) < strings.ToLower(schedules[j].StudentFullName)
+		}
+		return schedules[i].StudentTeacher < schedules[j].StudentTeacher
+	})
+
+	for _, schedule := range schedules {
+		err := tmpl.Execute(f, schedule)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/christophergm/miniclasses/model"
+)
+
+// studentCompare orders two students for one sort key, returning -1, 0 or +1
+type studentCompare func(a, b FinalAssignment) int
+
+// sortKeys are the keys accepted by the --sort spec, e.g. "grade,last,first".
+// Prefix a key with "-" to reverse it, e.g. "-grade,name".
+var sortKeys = map[string]studentCompare{
+	"grade": func(a, b FinalAssignment) int {
+		return cmp.Compare(a.StudentGrade, b.StudentGrade)
+	},
+	"first": func(a, b FinalAssignment) int {
+		return compareFold(firstName(a.StudentFullName), firstName(b.StudentFullName))
+	},
+	"last": func(a, b FinalAssignment) int {
+		return compareFold(lastName(a.StudentFullName), lastName(b.StudentFullName))
+	},
+	"name": func(a, b FinalAssignment) int {
+		return compareFold(a.StudentFullName, b.StudentFullName)
+	},
+	"teacher": func(a, b FinalAssignment) int {
+		return compareFold(a.StudentTeacher, b.StudentTeacher)
+	},
+	"stream": func(a, b FinalAssignment) int {
+		return compareFold(a.StudentStream, b.StudentStream)
+	},
+	"interest": func(a, b FinalAssignment) int {
+		return cmp.Compare(interestRank(a.StudentInterest), interestRank(b.StudentInterest))
+	},
+}
+
+// studentOrder is the order from the --sort spec; when nil each report keeps its own default order
+var studentOrder []studentCompare
+
+// Default orders: the class list sorts by grade then first name, the teacher reports by full name
+var (
+	classListOrder   = []studentCompare{sortKeys["grade"], sortKeys["first"]}
+	teacherListOrder = []studentCompare{sortKeys["name"]}
+)
+
+// parseSortSpec turns a comma separated list of sort keys into a student order
+func parseSortSpec(spec string) ([]studentCompare, error) {
+	var order []studentCompare
+	for _, key := range strings.Split(spec, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+
+		descending := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(key, "-")
+
+		compare, exists := sortKeys[key]
+		if !exists {
+			return nil, fmt.Errorf("unknown sort key %q, expected one of grade, first, last, name, teacher, stream, interest", key)
+		}
+		if descending {
+			ascending := compare
+			compare = func(a, b FinalAssignment) int {
+				return -ascending(a, b)
+			}
+		}
+		order = append(order, compare)
+	}
+	return order, nil
+}
+
+// Sort students by the --sort order, or by the given default order when none was set.
+// Ties are broken by full name so the output is the same on every run.
+func sortStudents(students []FinalAssignment, defaultOrder []studentCompare) {
+	order := studentOrder
+	if order == nil {
+		order = defaultOrder
+	}
+
+	sort.SliceStable(students, func(i, j int) bool {
+		return compareStudents(students[i], students[j], order) < 0
+	})
+}
+
+// Sort student infos the same way as sortStudents
+func sortStudentInfos(students []StudentInfo, defaultOrder []studentCompare) {
+	order := studentOrder
+	if order == nil {
+		order = defaultOrder
+	}
+
+	sort.SliceStable(students, func(i, j int) bool {
+		return compareStudents(students[i].FinalAssignment, students[j].FinalAssignment, order) < 0
+	})
+}
+
+func compareStudents(a, b FinalAssignment, order []studentCompare) int {
+	for _, compare := range order {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+	}
+	return compareFold(a.StudentFullName, b.StudentFullName)
+}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// studentNames are the students from the student list keyed by full name, so the first and last sort
+// keys use the name columns instead of guessing where a full name splits. When nil, or for students not
+// in the list, the full name is split after its first word.
+var studentNames map[string]model.Student
+
+// readStudentNames reads the student list used by the first and last sort keys
+func readStudentNames(file string) (map[string]model.Student, error) {
+	students, err := model.ReadStudents(file)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]model.Student, len(students))
+	for _, student := range students {
+		names[nameKey(student.FullName())] = student
+	}
+	return names, nil
+}
+
+// firstName is the first name from the student list, or else the first word of the full name
+func firstName(fullName string) string {
+	if student, exists := studentNames[nameKey(fullName)]; exists {
+		return student.FirstName
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(fullName), " ")
+	return first
+}
+
+// lastName is the last name from the student list, or else everything after the first word of the full name
+func lastName(fullName string) string {
+	if student, exists := studentNames[nameKey(fullName)]; exists {
+		return student.LastName
+	}
+	_, last, _ := strings.Cut(strings.TrimSpace(fullName), " ")
+	return strings.Join(strings.Fields(last), " ")
+}
+
+// nameKey normalizes a full name for matching across files
+func nameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Interest levels in order from most to least interested, as returned by interestRank
+const (
+	interestVery = iota
+	interestMaybe
+	interestNope
+	interestUnknown
+)
+
+// interestRank orders interest levels from most to least interested, with unknown levels last
+func interestRank(interest string) int {
+	level, known := model.ParseInterest(interest)
+	if !known {
+		return interestUnknown
+	}
+	switch level {
+	case model.VeryInterested:
+		return interestVery
+	case model.Interested:
+		return interestMaybe
+	}
+	return interestNope
+}
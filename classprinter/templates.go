@@ -0,0 +1,134 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// Default templates are compiled into the binary so the printer can run from any directory
+//
+//go:embed *_template.*
+var defaultTemplates embed.FS
+
+// templateDir optionally points to a directory whose templates override the embedded defaults.
+// Only the templates present in the directory are overridden; the rest fall back to the defaults.
+var templateDir string
+
+// templateFuncs are the helper functions available to default and custom templates
+var templateFuncs = template.FuncMap{
+	"join":       join,
+	"upper":      strings.ToUpper,
+	"gradeLabel": gradeLabel,
+	"default":    defaultValue,
+}
+
+// loadTemplate parses the named template from the override directory if present, otherwise from the embedded defaults.
+// The partials are parsed along with it so the template can use the blocks they {{define}}.
+func loadTemplate(name string, partials ...string) (*template.Template, error) {
+	content, err := readTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, partial := range partials {
+		content, err := readTemplate(partial)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tmpl.New(partial).Parse(string(content))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// loadHTMLTemplate is like loadTemplate but escapes the output for HTML documents
+func loadHTMLTemplate(name string) (*htmltemplate.Template, error) {
+	content, err := readTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	return htmltemplate.New(name).Funcs(htmltemplate.FuncMap(templateFuncs)).Parse(string(content))
+}
+
+// readTemplate returns the template source, preferring the override directory over the embedded defaults
+func readTemplate(name string) ([]byte, error) {
+	if templateDir != "" {
+		content, err := os.ReadFile(filepath.Join(templateDir, name))
+		if err == nil {
+			return content, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	content, err := defaultTemplates.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("no template named %q: %w", name, err)
+	}
+	return content, nil
+}
+
+// join concatenates the elements with the separator, e.g. {{ .Names | join ", " }}
+func join(sep string, elems []string) string {
+	return strings.Join(elems, sep)
+}
+
+// gradeLabel formats a grade number for display, e.g. 0 -> "K", 1 -> "1st", 4 -> "4th"
+func gradeLabel(grade int) string {
+	if grade == 0 {
+		return "K"
+	}
+	if grade < 0 {
+		return "Pre-K"
+	}
+
+	suffix := "th"
+	switch grade % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if grade%100 >= 11 && grade%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", grade, suffix)
+}
+
+// defaultValue returns the given value unless it is empty, in which case it returns the default,
+// e.g. {{ .Catalog.MeetLocation | default "TBD" }}
+func defaultValue(def any, given any) any {
+	if given == nil {
+		return def
+	}
+
+	v := reflect.ValueOf(given)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if v.Len() == 0 {
+			return def
+		}
+	default:
+		if v.IsZero() {
+			return def
+		}
+	}
+	return given
+}
@@ -1,3 +1,7 @@
 module github.com/christophergm/miniclasses/formparser
 
 go 1.23.0
+
+require github.com/christophergm/miniclasses/model v0.0.0
+
+replace github.com/christophergm/miniclasses/model => ../model
@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/christophergm/miniclasses/model"
 )
 
 func main() {
@@ -29,31 +31,6 @@ func main() {
 		return
 	}
 
-	// Open the output CSV files
-	timestamp := time.Now().Format("2006-01-02-1504")
-	fileName := fmt.Sprintf("adults-%s.csv", timestamp)
-	adultsFile, err := os.Create(fileName)
-	if err != nil {
-		fmt.Println("Error creating adults file:", err)
-		return
-	}
-	defer adultsFile.Close()
-
-	fileName = fmt.Sprintf("student_preferences-%s.csv", timestamp)
-	studentsFile, err := os.Create(fileName)
-	if err != nil {
-		fmt.Println("Error creating students file:", err)
-		return
-	}
-	defer adultsFile.Close()
-
-	// Create writers for the output CSVs
-	adultsFileWriter := csv.NewWriter(adultsFile)
-	defer adultsFileWriter.Flush()
-
-	studentsFileWriter := csv.NewWriter(studentsFile)
-	defer studentsFileWriter.Flush()
-
 	// Get headers from the input CSV (first row)
 	headers := rows[0]
 
@@ -76,29 +53,21 @@ func main() {
 	adultsHeader = append(adultsHeader, headers[54:70]...) // survey fields
 	adultsHeader = append(adultsHeader, "anything_else")   // other commend
 	adultsHeader = moveColumns16to18After5(adultsHeader)
-	err = adultsFileWriter.Write(adultsHeader)
-	if err != nil {
-		fmt.Println("Error writing headers:", err)
-		return
-	}
-	// Create header
-	studentsHeader := []string{}
-	studentsHeader = append(studentsHeader, "student_id")     // generated index for the adult
-	studentsHeader = append(studentsHeader, "household_id")   // generated index for the household
-	studentsHeader = append(studentsHeader, headers[2])       // student full name
-	studentsHeader = append(studentsHeader, headers[3:14]...) // interests
-	err = studentsFileWriter.Write(studentsHeader)
-	if err != nil {
-		fmt.Println("Error writing headers:", err)
-		return
+
+	// Interest areas from the interest column names, e.g. "interest_games_puzzles"
+	areas := []string{}
+	for _, colName := range headers[3:14] {
+		areas = append(areas, strings.TrimPrefix(colName, "interest_"))
 	}
 
 	// Process each row
+	adults := []model.Adult{}
+	students := []model.StudentPreference{}
 	adultIndex := 0
 	studentIndex := 0
 	householdIndex := 0
 	for _, row := range rows[1:] {
-		// Write adult fields
+		// Collect adult fields
 		adultIndex++
 		householdIndex++
 		adultRow := []string{}
@@ -110,11 +79,7 @@ func main() {
 		adultRow = append(adultRow, row[89:90]...) // duplicate of 'anything else' since only one per household
 		adultRow[4] = replaceParticipationLevel(adultRow[4])
 		adultRow = moveColumns16to18After5(adultRow)
-		err = adultsFileWriter.Write(adultRow)
-		if err != nil {
-			fmt.Println("Error writing row:", err)
-			return
-		}
+		adults = append(adults, newAdult(adultIndex, householdIndex, adultRow))
 
 		if row[70] == "Yes" {
 			adultIndex++
@@ -124,70 +89,46 @@ func main() {
 			adultRow = append(adultRow, row[71:90]...)
 			adultRow[4] = replaceParticipationLevel(adultRow[4])
 			adultRow = moveColumns16to18After5(adultRow)
-			err = adultsFileWriter.Write(adultRow)
-			if err != nil {
-				fmt.Println("Error writing additional row:", err)
-				return
-			}
+			adults = append(adults, newAdult(adultIndex, householdIndex, adultRow))
 		}
 
-		// Write student fields
+		// Collect student fields
 		studentIndex++
-		studentRow := []string{}
-		studentRow = append(studentRow, strconv.Itoa(studentIndex))
-		studentRow = append(studentRow, strconv.Itoa(householdIndex))
-		studentRow = append(studentRow, row[2])       // full name
-		studentRow = append(studentRow, row[3:14]...) // interests
-		err = studentsFileWriter.Write(studentRow)
-		if err != nil {
-			fmt.Println("Error writing row:", err)
-			return
-		}
+		students = append(students, newStudentPreference(studentIndex, householdIndex, row[2], areas, row[3:14]))
 
 		if row[14] == "Yes" {
 			studentIndex++
-			studentRow := []string{}
-			studentRow = append(studentRow, strconv.Itoa(studentIndex))
-			studentRow = append(studentRow, strconv.Itoa(householdIndex))
-			studentRow = append(studentRow, row[15])       // full name
-			studentRow = append(studentRow, row[16:27]...) // interests
-			err = studentsFileWriter.Write(studentRow)
-			if err != nil {
-				fmt.Println("Error writing row:", err)
-				return
-			}
+			students = append(students, newStudentPreference(studentIndex, householdIndex, row[15], areas, row[16:27]))
 		}
 
 		if row[27] == "Yes" {
 			studentIndex++
-			studentRow := []string{}
-			studentRow = append(studentRow, strconv.Itoa(studentIndex))
-			studentRow = append(studentRow, strconv.Itoa(householdIndex))
-			studentRow = append(studentRow, row[28])       // full name
-			studentRow = append(studentRow, row[29:40]...) // interests
-			err = studentsFileWriter.Write(studentRow)
-			if err != nil {
-				fmt.Println("Error writing row:", err)
-				return
-			}
+			students = append(students, newStudentPreference(studentIndex, householdIndex, row[28], areas, row[29:40]))
 		}
 
 		if row[40] == "Yes" {
 			studentIndex++
-			studentRow := []string{}
-			studentRow = append(studentRow, strconv.Itoa(studentIndex))
-			studentRow = append(studentRow, strconv.Itoa(householdIndex))
-			studentRow = append(studentRow, row[41])       // full name
-			studentRow = append(studentRow, row[42:53]...) // interests
-			err = studentsFileWriter.Write(studentRow)
-			if err != nil {
-				fmt.Println("Error writing row:", err)
-				return
-			}
+			students = append(students, newStudentPreference(studentIndex, householdIndex, row[41], areas, row[42:53]))
 		}
 
 	}
 
+	// Write the output CSV files
+	timestamp := time.Now().Format("2006-01-02-1504")
+	fileName := fmt.Sprintf("adults-%s.csv", timestamp)
+	err = model.WriteAdults(fileName, adultsHeader[4:], adults)
+	if err != nil {
+		fmt.Println("Error writing adults file:", err)
+		return
+	}
+
+	fileName = fmt.Sprintf("student_preferences-%s.csv", timestamp)
+	err = model.WriteStudentPreferences(fileName, areas, students)
+	if err != nil {
+		fmt.Println("Error writing students file:", err)
+		return
+	}
+
 	fmt.Println("CSV processing completed successfully.")
 }
 
@@ -235,3 +176,32 @@ func replaceParticipationLevel(col string) string {
 	}
 	return col
 }
+
+// newAdult builds an adult from an output row laid out like adultsHeader
+func newAdult(id int, householdID int, adultRow []string) model.Adult {
+	return model.Adult{
+		ID:          id,
+		HouseholdID: householdID,
+		FullName:    strings.TrimSpace(adultRow[2]),
+		Email:       strings.TrimSpace(adultRow[3]),
+		Extra:       adultRow[4:],
+	}
+}
+
+// newStudentPreference builds a student's preferences from their name and interest columns on the form
+func newStudentPreference(id int, householdID int, fullName string, areas []string, levels []string) model.StudentPreference {
+	preference := model.StudentPreference{
+		ID:          id,
+		HouseholdID: householdID,
+		FullName:    strings.TrimSpace(fullName),
+	}
+	for i, area := range areas {
+		level, known := model.ParseInterest(levels[i])
+		if !known {
+			// Keep blank or unexpected answers as written rather than guessing what was meant
+			level = model.Interest(levels[i])
+		}
+		preference.Interests = append(preference.Interests, model.AreaInterest{Area: area, Level: level})
+	}
+	return preference
+}
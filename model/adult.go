@@ -0,0 +1,116 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Adult is an adult from the sign up form. Extra holds the remaining survey answers in column order.
+type Adult struct {
+	ID          int
+	HouseholdID int
+	FullName    string
+	Email       string
+	Extra       []string
+}
+
+// AdultHeader is the header of the adults file followed by the names of the extra survey columns
+func AdultHeader(extraHeader []string) []string {
+	return append([]string{"adult_id", "household_id", "full_name", "email"}, extraHeader...)
+}
+
+// Record returns the adult as a row of the adults file
+func (a Adult) Record() []string {
+	return append([]string{strconv.Itoa(a.ID), strconv.Itoa(a.HouseholdID), a.FullName, a.Email}, a.Extra...)
+}
+
+// ReadAdults reads the adults file written by WriteAdults. Returns the names of the extra survey columns.
+func ReadAdults(file string) ([]Adult, []string, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = t.require(file, "adult_id", "household_id", "full_name", "email")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The extra columns are every column other than the fixed ones, wherever they are in the file
+	fixed := map[string]bool{"adult_id": true, "household_id": true, "full_name": true, "email": true}
+	var extraHeader []string
+	var extraColumns []int
+	for i, name := range t.header {
+		if !fixed[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] {
+			extraHeader = append(extraHeader, name)
+			extraColumns = append(extraColumns, i)
+		}
+	}
+
+	var adults []Adult
+	for i, row := range t.rows {
+		id, err := t.getInt(row, "adult_id")
+		if err != nil {
+			return nil, nil, rowError(file, i, err)
+		}
+		householdID, err := t.getInt(row, "household_id")
+		if err != nil {
+			return nil, nil, rowError(file, i, err)
+		}
+
+		adult := Adult{
+			ID:          id,
+			HouseholdID: householdID,
+			FullName:    t.get(row, "full_name"),
+			Email:       t.get(row, "email"),
+		}
+		for _, column := range extraColumns {
+			value := ""
+			if column < len(row) {
+				value = row[column]
+			}
+			adult.Extra = append(adult.Extra, value)
+		}
+		adults = append(adults, adult)
+	}
+	return adults, extraHeader, nil
+}
+
+// WriteAdults writes the adults file with the extra survey columns after the fixed columns
+func WriteAdults(file string, extraHeader []string, adults []Adult) error {
+	rows := make([][]string, 0, len(adults))
+	for _, adult := range adults {
+		rows = append(rows, adult.Record())
+	}
+	return writeTable(file, AdultHeader(extraHeader), rows)
+}
+
+// AdultAssignment is an adult assigned to help with or lead a class
+type AdultAssignment struct {
+	ClassID  string `json:"class_id"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Note     string `json:"note"`
+}
+
+// ReadAdultAssignments reads the adult class assignments (columns: class_id, full_name, email, note)
+func ReadAdultAssignments(file string) ([]AdultAssignment, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "class_id", "full_name")
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []AdultAssignment
+	for _, row := range t.rows {
+		assignments = append(assignments, AdultAssignment{
+			ClassID:  t.get(row, "class_id"),
+			FullName: t.get(row, "full_name"),
+			Email:    t.get(row, "email"),
+			Note:     t.get(row, "note"),
+		})
+	}
+	return assignments, nil
+}
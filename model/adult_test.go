@@ -0,0 +1,67 @@
+package model
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAdultsRoundTrip(t *testing.T) {
+	extraHeader := []string{"volunteer", "phone"}
+	adults := []Adult{
+		{ID: 1, HouseholdID: 1, FullName: "Pat Lee", Email: "pat@example.com", Extra: []string{"yes", "555-0100"}},
+		{ID: 2, HouseholdID: 2, FullName: "Sam Roe", Email: "", Extra: []string{"", ""}},
+	}
+
+	file := filepath.Join(t.TempDir(), "adults.csv")
+	err := WriteAdults(file, extraHeader, adults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, gotHeader, err := ReadAdults(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotHeader, extraHeader) {
+		t.Errorf("extra header = %v, want %v", gotHeader, extraHeader)
+	}
+	if !reflect.DeepEqual(got, adults) {
+		t.Errorf("ReadAdults = %+v, want %+v", got, adults)
+	}
+}
+
+func TestReadAdultsExtraColumnsByName(t *testing.T) {
+	// The extra columns come before and between the fixed ones
+	file := writeFile(t, "adults.csv", "volunteer,adult_id,full_name,phone,household_id,email\n"+
+		"yes,1,Pat Lee,555-0100,1,pat@example.com\n"+
+		"no,2,Sam Roe\n")
+	adults, extraHeader, err := ReadAdults(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"volunteer", "phone"}; !reflect.DeepEqual(extraHeader, want) {
+		t.Errorf("extra header = %v, want %v", extraHeader, want)
+	}
+	want := []Adult{
+		{ID: 1, HouseholdID: 1, FullName: "Pat Lee", Email: "pat@example.com", Extra: []string{"yes", "555-0100"}},
+		{ID: 2, FullName: "Sam Roe", Extra: []string{"no", ""}},
+	}
+	if !reflect.DeepEqual(adults, want) {
+		t.Errorf("ReadAdults = %+v, want %+v", adults, want)
+	}
+}
+
+func TestReadAdultAssignments(t *testing.T) {
+	file := writeFile(t, "adult_class_assignments.csv", "class_id,full_name,email,note\nS1-01,Pat Lee,pat@example.com,leader\nS1-02,Sam Roe\n")
+	assignments, err := ReadAdultAssignments(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AdultAssignment{
+		{ClassID: "S1-01", FullName: "Pat Lee", Email: "pat@example.com", Note: "leader"},
+		{ClassID: "S1-02", FullName: "Sam Roe"},
+	}
+	if !reflect.DeepEqual(assignments, want) {
+		t.Errorf("ReadAdultAssignments = %+v, want %+v", assignments, want)
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import "strconv"
+
+// Assignment is a student's final class assignment for a session
+type Assignment struct {
+	ClassName       string `json:"class_name"`
+	ClassSession    int    `json:"class_session"`
+	ClassID         string `json:"class_id"`
+	StudentFullName string `json:"student_full_name"`
+	StudentGrade    int    `json:"student_grade"`
+	StudentTeacher  string `json:"student_teacher"`
+	StudentStream   string `json:"student_stream"`
+	StudentInterest string `json:"student_interest"`
+}
+
+// AssignmentHeader is the header of the final assignments file
+var AssignmentHeader = []string{
+	"class_name", "class_session", "class_id", "student_full_name", "student_grade",
+	"student_teacher", "student_stream", "student_interest",
+}
+
+// Record returns the assignment as a row of the final assignments file
+func (a Assignment) Record() []string {
+	return []string{
+		a.ClassName,
+		strconv.Itoa(a.ClassSession),
+		a.ClassID,
+		a.StudentFullName,
+		strconv.Itoa(a.StudentGrade),
+		a.StudentTeacher,
+		a.StudentStream,
+		a.StudentInterest,
+	}
+}
+
+// ReadAssignments reads the final assignments file
+func ReadAssignments(file string) ([]Assignment, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "class_id", "student_full_name")
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []Assignment
+	for i, row := range t.rows {
+		session, err := t.getInt(row, "class_session")
+		if err != nil {
+			return nil, rowError(file, i, err)
+		}
+		grade, err := t.getInt(row, "student_grade")
+		if err != nil {
+			return nil, rowError(file, i, err)
+		}
+
+		assignments = append(assignments, Assignment{
+			ClassName:       t.get(row, "class_name"),
+			ClassSession:    session,
+			ClassID:         t.get(row, "class_id"),
+			StudentFullName: t.get(row, "student_full_name"),
+			StudentGrade:    grade,
+			StudentTeacher:  t.get(row, "student_teacher"),
+			StudentStream:   t.get(row, "student_stream"),
+			StudentInterest: t.get(row, "student_interest"),
+		})
+	}
+	return assignments, nil
+}
+
+// WriteAssignments writes the final assignments file
+func WriteAssignments(file string, assignments []Assignment) error {
+	rows := make([][]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		rows = append(rows, assignment.Record())
+	}
+	return writeTable(file, AssignmentHeader, rows)
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAssignmentsRoundTrip(t *testing.T) {
+	assignments := []Assignment{
+		{ClassName: "Strategy Games", ClassSession: 1, ClassID: "S1-01", StudentFullName: "Ada Lee", StudentGrade: 3,
+			StudentTeacher: "Todd", StudentStream: "green", StudentInterest: string(VeryInterested)},
+		{ClassName: "Fallback", ClassSession: 1, StudentFullName: "Ben, Jr. Lee", StudentGrade: 5},
+	}
+
+	file := filepath.Join(t.TempDir(), "final_assignments.csv")
+	err := WriteAssignments(file, assignments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := readFile(t, file)
+
+	got, err := ReadAssignments(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, assignments) {
+		t.Errorf("ReadAssignments = %+v, want %+v", got, assignments)
+	}
+
+	// Writing what was read gives the same bytes, so runs with the same seed can be compared as files
+	err = WriteAssignments(file, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := readFile(t, file); again != written {
+		t.Errorf("file changed when written back:\n%s\nwant\n%s", again, written)
+	}
+}
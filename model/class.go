@@ -0,0 +1,96 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Class is a class offered in a session, from the class catalog
+type Class struct {
+	ID              string   `json:"id"`
+	Session         int      `json:"session"`
+	Name            string   `json:"name"`
+	InterestArea    string   `json:"interest_area"`
+	GradeMin        int      `json:"grade_min"`
+	GradeMax        int      `json:"grade_max"`
+	StudentCapacity int      `json:"student_capacity_max"`
+	Location        string   `json:"location"`
+	MeetLocation    string   `json:"meet_location"`
+	MeetingDates    []string `json:"meeting_dates"` // YYYY-MM-DD
+	StartTime       string   `json:"start_time"`    // HH:MM
+	EndTime         string   `json:"end_time"`      // HH:MM
+
+	// StudentCapacityMin is the fewest students the class runs with, 0 when there is no minimum
+	StudentCapacityMin int `json:"student_capacity_min"`
+}
+
+// AcceptsGrade reports whether a student in the given grade is eligible for the class
+func (c Class) AcceptsGrade(grade int) bool {
+	return grade >= c.GradeMin && grade <= c.GradeMax
+}
+
+// ReadClasses reads the class catalog. The location, meet_location, meeting_dates, start_time,
+// end_time and student_capacity_min columns are optional. Meeting dates are separated by semicolons.
+func ReadClasses(file string) ([]Class, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "id", "session", "name", "interest_area", "grade_min", "grade_max", "student_capacity_max")
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []Class
+	for i, row := range t.rows {
+		class := Class{
+			ID:           t.get(row, "id"),
+			Name:         t.get(row, "name"),
+			InterestArea: t.get(row, "interest_area"),
+			Location:     t.get(row, "location"),
+			MeetLocation: t.get(row, "meet_location"),
+			StartTime:    t.get(row, "start_time"),
+			EndTime:      t.get(row, "end_time"),
+		}
+
+		for _, field := range []struct {
+			column string
+			value  *int
+		}{
+			{"session", &class.Session},
+			{"grade_min", &class.GradeMin},
+			{"grade_max", &class.GradeMax},
+			{"student_capacity_max", &class.StudentCapacity},
+			{"student_capacity_min", &class.StudentCapacityMin},
+		} {
+			*field.value, err = t.getInt(row, field.column)
+			if err != nil {
+				return nil, rowError(file, i, err)
+			}
+		}
+
+		for _, date := range strings.Split(t.get(row, "meeting_dates"), ";") {
+			date = strings.TrimSpace(date)
+			if date == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return nil, rowError(file, i, fmt.Errorf("class %s: invalid meeting date %q, expected YYYY-MM-DD", class.ID, date))
+			}
+			class.MeetingDates = append(class.MeetingDates, date)
+		}
+		sort.Strings(class.MeetingDates)
+
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// sortByIndex sorts the items by an integer key, keeping the order of equal keys
+func sortByIndex[T any](items []T, key func(T) int) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return key(items[i]) < key(items[j])
+	})
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadClasses(t *testing.T) {
+	file := writeFile(t, "class_catalog.csv", "id,session,name,interest_area,grade_min,grade_max,student_capacity_max,meeting_dates,start_time,student_capacity_min\n"+
+		"S1-01,1,Strategy Games,games_puzzles,1,3,8,2025-03-11; 2025-03-04,14:00,4\n"+
+		"S1-02,1,Clay,arts_crafts,2,6,10,,,\n")
+	classes, err := ReadClasses(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Class{
+		{ID: "S1-01", Session: 1, Name: "Strategy Games", InterestArea: "games_puzzles", GradeMin: 1, GradeMax: 3, StudentCapacity: 8,
+			MeetingDates: []string{"2025-03-04", "2025-03-11"}, StartTime: "14:00", StudentCapacityMin: 4},
+		{ID: "S1-02", Session: 1, Name: "Clay", InterestArea: "arts_crafts", GradeMin: 2, GradeMax: 6, StudentCapacity: 10},
+	}
+	if !reflect.DeepEqual(classes, want) {
+		t.Errorf("ReadClasses = %+v, want %+v", classes, want)
+	}
+	if !classes[0].AcceptsGrade(3) || classes[0].AcceptsGrade(4) {
+		t.Errorf("AcceptsGrade does not follow grades %d to %d", classes[0].GradeMin, classes[0].GradeMax)
+	}
+}
+
+func TestReadClassesErrors(t *testing.T) {
+	header := "id,session,name,interest_area,grade_min,grade_max,student_capacity_max,meeting_dates\n"
+	for name, contents := range map[string]string{
+		"missing column": "id,session,name\nS1-01,1,Games\n",
+		"bad number":     header + "S1-01,1,Games,games,one,3,8,\n",
+		"bad date":       header + "S1-01,1,Games,games,1,3,8,3/4/2025\n",
+	} {
+		_, err := ReadClasses(writeFile(t, "class_catalog.csv", contents))
+		if err == nil {
+			t.Errorf("%s: ReadClasses succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// table is a CSV file read into rows with its columns looked up by header name, so files
+// with extra, missing or reordered optional columns can still be read
+type table struct {
+	header  []string
+	columns map[string]int
+	rows    [][]string
+}
+
+// readTable reads a CSV file with a header row. Rows may have fewer fields than the header.
+func readTable(file string) (*table, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: missing header row", file)
+	}
+
+	t := &table{header: records[0], columns: make(map[string]int), rows: records[1:]}
+	for i, name := range records[0] {
+		// Strip the byte order mark spreadsheets add to the first column
+		name = strings.TrimPrefix(name, "\ufeff")
+		t.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return t, nil
+}
+
+// Table is a CSV file with every row kept as written, for tools that pass through columns they don't know
+type Table struct {
+	Header []string
+	Rows   [][]string
+	t      *table
+}
+
+// ReadTable reads a CSV file with a header row, keeping every column
+func ReadTable(file string) (Table, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return Table{}, err
+	}
+	return Table{Header: t.header, Rows: t.rows, t: t}, nil
+}
+
+// Has reports whether the table has any of the columns
+func (t Table) Has(names ...string) bool {
+	return t.t.has(names...)
+}
+
+// Require returns an error naming the first column the table is missing
+func (t Table) Require(file string, names ...string) error {
+	return t.t.require(file, names...)
+}
+
+// Get returns the trimmed value of the first of the named columns present in the table
+func (t Table) Get(row []string, names ...string) string {
+	return t.t.get(row, names...)
+}
+
+// has reports whether the table has any of the columns
+func (t *table) has(names ...string) bool {
+	for _, name := range names {
+		if _, exists := t.columns[name]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the trimmed value of the first of the named columns present in the table,
+// or an empty string if none is present or the row is short
+func (t *table) get(row []string, names ...string) string {
+	for _, name := range names {
+		if i, exists := t.columns[name]; exists {
+			if i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// getInt returns the value of the column as an integer, treating an empty value as zero
+func (t *table) getInt(row []string, names ...string) (int, error) {
+	value := t.get(row, names...)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("column %s: invalid number %q", names[0], value)
+	}
+	return n, nil
+}
+
+// require returns an error naming the first column the table is missing
+func (t *table) require(file string, names ...string) error {
+	for _, name := range names {
+		if !t.has(name) {
+			return fmt.Errorf("%s: missing column %s", file, name)
+		}
+	}
+	return nil
+}
+
+// writeTable writes the header and rows to a CSV file
+func writeTable(file string, header []string, rows [][]string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(f)
+	err = writer.Write(header)
+	if err == nil {
+		err = writer.WriteAll(rows)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rowError prefixes an error with the file and line number of the row, counting the header as line 1
+func rowError(file string, i int, err error) error {
+	return fmt.Errorf("%s line %d: %w", file, i+2, err)
+}
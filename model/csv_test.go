@@ -0,0 +1,83 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFile writes the contents to a file in a temporary directory and returns its path
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(file, []byte(contents), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// readFile returns the contents of the file
+func readFile(t *testing.T, file string) string {
+	t.Helper()
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(contents)
+}
+
+func TestReadTable(t *testing.T) {
+	file := writeFile(t, "table.csv", "\ufeffFirst_Name, notes\nAda,quiet\nBen\n")
+	table, err := ReadTable(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"\ufeffFirst_Name", " notes"}; !reflect.DeepEqual(table.Header, want) {
+		t.Errorf("Header = %q, want the header as written %q", table.Header, want)
+	}
+	if !table.Has("first_name") || table.Has("last_name") {
+		t.Errorf("Has matched the wrong columns of %q", table.Header)
+	}
+	if got := table.Get(table.Rows[0], "notes"); got != "quiet" {
+		t.Errorf("Get notes = %q, want quiet", got)
+	}
+	if got := table.Get(table.Rows[1], "notes"); got != "" {
+		t.Errorf("Get notes of a short row = %q, want empty", got)
+	}
+	if err := table.Require(file, "first_name", "grade"); err == nil {
+		t.Errorf("Require grade succeeded on %q", table.Header)
+	}
+}
+
+func TestReadTableEmpty(t *testing.T) {
+	_, err := ReadTable(writeFile(t, "empty.csv", ""))
+	if err == nil {
+		t.Errorf("ReadTable of an empty file succeeded, want a missing header error")
+	}
+}
+
+func TestWriteTableError(t *testing.T) {
+	err := WriteSettings(filepath.Join(t.TempDir(), "missing", "run.csv"), []Setting{{Name: "seed", Value: "1"}})
+	if err == nil {
+		t.Errorf("writing to a missing directory succeeded")
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "run.csv")
+	settings := []Setting{{Name: "seed", Value: "42"}, {Name: "weights", Value: "interest=1,stream=0.5"}, {Name: "cancel", Value: ""}}
+	err := WriteSettings(file, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadSettings(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, settings) {
+		t.Errorf("ReadSettings = %v, want %v", got, settings)
+	}
+}
@@ -0,0 +1,6 @@
+// Package model holds the data types shared by the mini class tools, along with readers and
+// writers for the CSV files they are stored in.
+//
+// Readers look up columns by header name so optional columns may be left off, and writers
+// always write every column in a fixed order.
+package model
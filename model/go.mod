@@ -0,0 +1,3 @@
+module github.com/christophergm/miniclasses/model
+
+go 1.23.0
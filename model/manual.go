@@ -0,0 +1,55 @@
+package model
+
+// ClassStudent pairs a student with a class, as in the manual assignments and exclusions files
+type ClassStudent struct {
+	ClassID         string
+	StudentFullName string
+}
+
+// ReadClassStudents reads a manual assignments or exclusions file (columns: class_id, student_full_name)
+func ReadClassStudents(file string) ([]ClassStudent, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "class_id", "student_full_name")
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []ClassStudent
+	for _, row := range t.rows {
+		pairs = append(pairs, ClassStudent{
+			ClassID:         t.get(row, "class_id"),
+			StudentFullName: t.get(row, "student_full_name"),
+		})
+	}
+	return pairs, nil
+}
+
+// Skip is a student left out of the assignment, e.g. because they are in the wrong grade
+type Skip struct {
+	FullName string
+	Reason   string
+}
+
+// ReadSkips reads the skip assignments file (columns: full_name, reason)
+func ReadSkips(file string) ([]Skip, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "full_name")
+	if err != nil {
+		return nil, err
+	}
+
+	var skips []Skip
+	for _, row := range t.rows {
+		skips = append(skips, Skip{
+			FullName: t.get(row, "full_name"),
+			Reason:   t.get(row, "reason"),
+		})
+	}
+	return skips, nil
+}
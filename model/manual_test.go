@@ -0,0 +1,29 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadClassStudents(t *testing.T) {
+	file := writeFile(t, "class_assignments_manual.csv", "class_id,student_full_name\nS1-01, Ada Lee \n")
+	pairs, err := ReadClassStudents(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ClassStudent{{ClassID: "S1-01", StudentFullName: "Ada Lee"}}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("ReadClassStudents = %+v, want %+v", pairs, want)
+	}
+}
+
+func TestReadSkips(t *testing.T) {
+	file := writeFile(t, "skip_assignments_manual.csv", "full_name,reason\nAda Lee,moved away\nBen Lee\n")
+	skips, err := ReadSkips(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Skip{{FullName: "Ada Lee", Reason: "moved away"}, {FullName: "Ben Lee"}}
+	if !reflect.DeepEqual(skips, want) {
+		t.Errorf("ReadSkips = %+v, want %+v", skips, want)
+	}
+}
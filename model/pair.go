@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PairRelation says whether two students should be in the same class or in different classes
+type PairRelation string
+
+const (
+	Together PairRelation = "together"
+	Apart    PairRelation = "apart"
+)
+
+// PairStrength says whether a pair request must be met or is met where possible
+type PairStrength string
+
+const (
+	// Hard requests are always met, or reported when they cannot be
+	Hard PairStrength = "hard"
+
+	// Soft requests are met where that doesn't cost too much elsewhere
+	Soft PairStrength = "soft"
+)
+
+// StudentPair is a request about two students, such as friends who asked to be in the same class
+// or siblings whose parents asked to keep them apart
+type StudentPair struct {
+	StudentA string
+	StudentB string
+	Relation PairRelation
+	Strength PairStrength
+}
+
+// ReadStudentPairs reads the pair requests file (columns: student_a, student_b, relation, strength).
+// The relation and strength columns are optional, a request is together and soft unless they say otherwise.
+func ReadStudentPairs(file string) ([]StudentPair, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "student_a", "student_b")
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []StudentPair
+	for i, row := range t.rows {
+		pair := StudentPair{
+			StudentA: t.get(row, "student_a"),
+			StudentB: t.get(row, "student_b"),
+			Relation: Together,
+			Strength: Soft,
+		}
+
+		switch relation := PairRelation(strings.ToLower(t.get(row, "relation"))); relation {
+		case Together, Apart:
+			pair.Relation = relation
+		case "":
+		default:
+			return nil, rowError(file, i, fmt.Errorf("column relation: expected together or apart, got %q", relation))
+		}
+
+		switch strength := PairStrength(strings.ToLower(t.get(row, "strength"))); strength {
+		case Hard, Soft:
+			pair.Strength = strength
+		case "":
+		default:
+			return nil, rowError(file, i, fmt.Errorf("column strength: expected hard or soft, got %q", strength))
+		}
+
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadStudentPairs(t *testing.T) {
+	file := writeFile(t, "pair_requests.csv", "student_a,student_b,relation,strength\n"+
+		"Ada Lee,Ben Lee,,\n"+
+		"Ada Lee,Cal Roe,Apart,HARD\n")
+	pairs, err := ReadStudentPairs(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []StudentPair{
+		{StudentA: "Ada Lee", StudentB: "Ben Lee", Relation: Together, Strength: Soft},
+		{StudentA: "Ada Lee", StudentB: "Cal Roe", Relation: Apart, Strength: Hard},
+	}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("ReadStudentPairs = %+v, want %+v", pairs, want)
+	}
+}
+
+func TestReadStudentPairsErrors(t *testing.T) {
+	for name, contents := range map[string]string{
+		"bad relation": "student_a,student_b,relation\nAda Lee,Ben Lee,near\n",
+		"bad strength": "student_a,student_b,strength\nAda Lee,Ben Lee,very\n",
+	} {
+		_, err := ReadStudentPairs(writeFile(t, "pair_requests.csv", contents))
+		if err == nil {
+			t.Errorf("%s: ReadStudentPairs succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+// Setting is a named value recorded alongside an output file, such as the seed of an assignment run
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// SettingHeader is the header of a settings file
+var SettingHeader = []string{"setting", "value"}
+
+// Record returns the setting as a row of a settings file
+func (s Setting) Record() []string {
+	return []string{s.Name, s.Value}
+}
+
+// ReadSettings reads a settings file (columns: setting, value)
+func ReadSettings(file string) ([]Setting, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "setting", "value")
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []Setting
+	for _, row := range t.rows {
+		settings = append(settings, Setting{
+			Name:  t.get(row, "setting"),
+			Value: t.get(row, "value"),
+		})
+	}
+	return settings, nil
+}
+
+// WriteSettings writes a settings file
+func WriteSettings(file string, settings []Setting) error {
+	rows := make([][]string, 0, len(settings))
+	for _, setting := range settings {
+		rows = append(rows, setting.Record())
+	}
+	return writeTable(file, SettingHeader, rows)
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Interest is how interested a student is in an interest area, as labeled on the sign up form
+type Interest string
+
+const (
+	VeryInterested Interest = "Very Interested"
+	Interested     Interest = "Interested"
+	NotInterested  Interest = "Not at all interested"
+)
+
+// ParseInterest returns the interest level for a form label, ignoring case and surrounding space.
+// The second result is false when the label is not a known level.
+func ParseInterest(label string) (Interest, bool) {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case strings.ToLower(string(VeryInterested)):
+		return VeryInterested, true
+	case strings.ToLower(string(Interested)):
+		return Interested, true
+	case strings.ToLower(string(NotInterested)):
+		return NotInterested, true
+	}
+	return NotInterested, false
+}
+
+// Student is a student from the school directory. HouseholdID comes from the optional household_id
+// column and is zero when the list has none.
+type Student struct {
+	FirstName   string
+	LastName    string
+	Grade       int
+	Teacher     string
+	Stream      string
+	HouseholdID int
+}
+
+// FullName is the first and last name, which is how students are matched across files
+func (s Student) FullName() string {
+	return strings.TrimSpace(s.FirstName) + " " + strings.TrimSpace(s.LastName)
+}
+
+// ReadStudents reads the student list (columns: first_name, last_name, grade, teacher, stream and
+// optionally household_id)
+func ReadStudents(file string) ([]Student, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	err = t.require(file, "first_name", "last_name", "grade")
+	if err != nil {
+		return nil, err
+	}
+
+	var students []Student
+	for i, row := range t.rows {
+		grade, err := t.getInt(row, "grade")
+		if err != nil {
+			return nil, rowError(file, i, err)
+		}
+		householdID, err := t.getInt(row, "household_id")
+		if err != nil {
+			return nil, rowError(file, i, err)
+		}
+		students = append(students, Student{
+			FirstName:   t.get(row, "first_name"),
+			LastName:    t.get(row, "last_name"),
+			Grade:       grade,
+			Teacher:     t.get(row, "teacher"),
+			Stream:      t.get(row, "stream"),
+			HouseholdID: householdID,
+		})
+	}
+	return students, nil
+}
+
+// AreaInterest is a student's interest in one interest area
+type AreaInterest struct {
+	Area  string
+	Level Interest
+}
+
+// StudentPreference is a student's interests from the sign up form
+type StudentPreference struct {
+	ID          int
+	HouseholdID int
+	FullName    string
+	Interests   []AreaInterest // in form column order
+}
+
+// Interest returns the student's interest in the area, or NotInterested if the area is not listed
+func (p StudentPreference) Interest(area string) Interest {
+	for _, interest := range p.Interests {
+		if interest.Area == area {
+			return interest.Level
+		}
+	}
+	return NotInterested
+}
+
+// Areas lists the interest areas in column order
+func (p StudentPreference) Areas() []string {
+	areas := make([]string, 0, len(p.Interests))
+	for _, interest := range p.Interests {
+		areas = append(areas, interest.Area)
+	}
+	return areas
+}
+
+// interestPrefix marks the interest columns of the preferences file, e.g. "interest_games_puzzles"
+const interestPrefix = "interest_"
+
+// StudentPreferenceHeader is the header of the preferences file for the interest areas
+func StudentPreferenceHeader(areas []string) []string {
+	header := []string{"student_id", "household_id", "full_name"}
+	for _, area := range areas {
+		header = append(header, interestPrefix+area)
+	}
+	return header
+}
+
+// Record returns the preferences as a row of the preferences file with the interests in the order of the areas
+func (p StudentPreference) Record(areas []string) []string {
+	record := []string{strconv.Itoa(p.ID), strconv.Itoa(p.HouseholdID), p.FullName}
+	for _, area := range areas {
+		record = append(record, string(p.Interest(area)))
+	}
+	return record
+}
+
+// ReadStudentPreferences reads the preferences file (columns: full_name, interest_<area>..., and
+// optionally student_id and household_id). Unknown interest labels are kept as written.
+// Returns the interest areas in column order.
+func ReadStudentPreferences(file string) ([]StudentPreference, []string, error) {
+	t, err := readTable(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !t.has("full_name", "student_full_name") {
+		return nil, nil, fmt.Errorf("%s: missing column full_name", file)
+	}
+
+	// Collect the interest columns in order, accepting a student_ prefix as written by older forms
+	type areaColumn struct {
+		area  string
+		index int
+	}
+	var areaColumns []areaColumn
+	for name, i := range t.columns {
+		area, found := strings.CutPrefix(strings.TrimPrefix(name, "student_"), interestPrefix)
+		if found {
+			areaColumns = append(areaColumns, areaColumn{area: area, index: i})
+		}
+	}
+	sortByIndex(areaColumns, func(c areaColumn) int { return c.index })
+
+	areas := make([]string, 0, len(areaColumns))
+	for _, column := range areaColumns {
+		areas = append(areas, column.area)
+	}
+
+	var prefs []StudentPreference
+	for i, row := range t.rows {
+		id, err := t.getInt(row, "student_id")
+		if err != nil {
+			return nil, nil, rowError(file, i, err)
+		}
+		householdID, err := t.getInt(row, "household_id")
+		if err != nil {
+			return nil, nil, rowError(file, i, err)
+		}
+
+		pref := StudentPreference{
+			ID:          id,
+			HouseholdID: householdID,
+			FullName:    t.get(row, "full_name", "student_full_name"),
+		}
+		for _, column := range areaColumns {
+			level := ""
+			if column.index < len(row) {
+				level = row[column.index]
+			}
+			interest, known := ParseInterest(level)
+			if !known {
+				// Keep blank or unexpected answers as written rather than guessing what was meant
+				interest = Interest(level)
+			}
+			pref.Interests = append(pref.Interests, AreaInterest{Area: column.area, Level: interest})
+		}
+		prefs = append(prefs, pref)
+	}
+	return prefs, areas, nil
+}
+
+// WriteStudentPreferences writes the preferences file with the interest columns in the order of the areas
+func WriteStudentPreferences(file string, areas []string, prefs []StudentPreference) error {
+	rows := make([][]string, 0, len(prefs))
+	for _, pref := range prefs {
+		rows = append(rows, pref.Record(areas))
+	}
+	return writeTable(file, StudentPreferenceHeader(areas), rows)
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadStudents(t *testing.T) {
+	file := writeFile(t, "student_list.csv", "first_name,last_name,grade,teacher,stream,household_id,notes\n"+
+		"Mary Ann,Smith,3,Todd,green,7,x\n"+
+		"Ben,Jones,5,Ruiz,blue,,\n")
+	students, err := ReadStudents(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Student{
+		{FirstName: "Mary Ann", LastName: "Smith", Grade: 3, Teacher: "Todd", Stream: "green", HouseholdID: 7},
+		{FirstName: "Ben", LastName: "Jones", Grade: 5, Teacher: "Ruiz", Stream: "blue"},
+	}
+	if !reflect.DeepEqual(students, want) {
+		t.Errorf("ReadStudents = %+v, want %+v", students, want)
+	}
+	if got := students[0].FullName(); got != "Mary Ann Smith" {
+		t.Errorf("FullName = %q, want Mary Ann Smith", got)
+	}
+}
+
+func TestReadStudentsBadGrade(t *testing.T) {
+	_, err := ReadStudents(writeFile(t, "student_list.csv", "first_name,last_name,grade\nAda,Lee,third\n"))
+	if err == nil {
+		t.Errorf("ReadStudents of a non-numeric grade succeeded")
+	}
+}
+
+func TestStudentPreferencesRoundTrip(t *testing.T) {
+	areas := []string{"games_puzzles", "arts_crafts"}
+	prefs := []StudentPreference{
+		{ID: 1, HouseholdID: 1, FullName: "Ada Lee", Interests: []AreaInterest{
+			{Area: "games_puzzles", Level: VeryInterested}, {Area: "arts_crafts", Level: NotInterested}}},
+		{ID: 2, HouseholdID: 1, FullName: "Ben Lee", Interests: []AreaInterest{
+			{Area: "games_puzzles", Level: Interested}, {Area: "arts_crafts", Level: ""}}},
+	}
+
+	file := filepath.Join(t.TempDir(), "student_preferences.csv")
+	err := WriteStudentPreferences(file, areas, prefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, gotAreas, err := ReadStudentPreferences(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotAreas, areas) {
+		t.Errorf("areas = %v, want %v", gotAreas, areas)
+	}
+	if !reflect.DeepEqual(got, prefs) {
+		t.Errorf("ReadStudentPreferences = %+v, want %+v", got, prefs)
+	}
+}
+
+func TestReadStudentPreferencesKeepsUnknownLabels(t *testing.T) {
+	file := writeFile(t, "student_preferences.csv", "full_name,student_interest_cooking,interest_music\n"+
+		"Ada Lee, very interested ,Maybe\n")
+	prefs, areas, err := ReadStudentPreferences(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cooking", "music"}; !reflect.DeepEqual(areas, want) {
+		t.Errorf("areas = %v, want %v", areas, want)
+	}
+	want := []AreaInterest{{Area: "cooking", Level: VeryInterested}, {Area: "music", Level: "Maybe"}}
+	if !reflect.DeepEqual(prefs[0].Interests, want) {
+		t.Errorf("interests = %+v, want %+v", prefs[0].Interests, want)
+	}
+}
@@ -6,56 +6,37 @@ import (
 	"os"
 	"strings"
 	"time"
-)
 
-type Student struct {
-	FirstName string
-	LastName  string
-	Grade     string
-	Teacher   string
-	Stream    string
-	Interests []string
-}
+	"github.com/christophergm/miniclasses/model"
+)
 
 func main() {
-	// Open the first CSV file
-	file1, err := os.Open("student_list.csv")
-	if err != nil {
-		fmt.Println("Error opening first file:", err)
-		return
+	// Read the student list and the student preferences with every column they have, so the merged
+	// file keeps columns such as household_id that the join itself doesn't use
+	students, err := model.ReadTable("student_list.csv")
+	if err == nil {
+		err = students.Require("student_list.csv", "first_name", "last_name")
 	}
-	defer file1.Close()
-
-	// Open the second CSV file
-	file2, err := os.Open("student_preferences.csv")
 	if err != nil {
-		fmt.Println("Error opening second file:", err)
+		fmt.Println("Error reading student list:", err)
 		return
 	}
-	defer file2.Close()
 
-	// Read the first CSV file
-	reader1 := csv.NewReader(file1)
-	records1, err := reader1.ReadAll()
-	if err != nil {
-		fmt.Println("Error reading first file:", err)
-		return
+	preferences, err := model.ReadTable("student_preferences.csv")
+	if err == nil && !preferences.Has("full_name", "student_full_name") {
+		err = fmt.Errorf("student_preferences.csv: missing column full_name")
 	}
-
-	// Read the second CSV file
-	reader2 := csv.NewReader(file2)
-	records2, err := reader2.ReadAll()
 	if err != nil {
-		fmt.Println("Error reading second file:", err)
+		fmt.Println("Error reading student preferences:", err)
 		return
 	}
 
-	// Create a map for the second file where full_name is the key
+	// Create a map for the preferences where full_name is the key
 	studentInterests := make(map[string][]string)
-	matched := make(map[string]bool) // To track which records in file2 got matched
+	matched := make(map[string]bool) // To track which preferences got matched
 
-	for _, record := range records2[1:] { // Skipping header row
-		fullName := strings.TrimSpace(record[2]) // "full_name"
+	for _, record := range preferences.Rows {
+		fullName := preferences.Get(record, "full_name", "student_full_name")
 		studentInterests[fullName] = record
 		matched[fullName] = false
 	}
@@ -74,25 +55,23 @@ func main() {
 	defer writer.Flush()
 
 	// Write the header row to the new CSV
-	header := append(records1[0], records2[0]...) // Combine headers
+	header := append(append([]string{}, students.Header...), preferences.Header...) // Combine headers
 	writer.Write(header)
 
-	// Process each row of the first file
-	for _, record1 := range records1[1:] {
-		firstName := strings.TrimSpace(record1[0]) // "first_name"
-		lastName := strings.TrimSpace(record1[1])  // "last_name"
-		fullName := firstName + " " + lastName
+	// Process each student in the list
+	for _, record := range students.Rows {
+		fullName := model.Student{FirstName: students.Get(record, "first_name"), LastName: students.Get(record, "last_name")}.FullName()
 
-		// Check if the full name exists in the second file's map
+		// Check if the full name exists in the preferences
 		if interests, ok := studentInterests[fullName]; ok {
-			// Combine the row with the interests
-			combinedRecord := append(record1, interests...)
+			// Pad short rows so the preferences line up with their columns
+			combinedRecord := append(pad(record, len(students.Header)), interests...)
 			writer.Write(combinedRecord)
 			// Mark this full_name as matched
 			matched[fullName] = true
 		} else {
 			// Write without interests if no match found
-			writer.Write(record1)
+			writer.Write(record)
 		}
 	}
 
@@ -107,21 +86,24 @@ func main() {
 	}
 	defer unmatchedFile.Close()
 
-	// Write to the unmatched CSV file
+	// Write the unmatched preferences with the same columns as the preferences file
 	unmatchedWriter := csv.NewWriter(unmatchedFile)
 	defer unmatchedWriter.Flush()
 
-	// Write the header row for the unmatched CSV (same as file2)
-	unmatchedWriter.Write(records2[0])
-
-	// Write all unmatched rows from file2 to the unmatched.csv
-	for _, record := range records2[1:] {
-		fullName := strings.TrimSpace(record[2])
-		if !matched[fullName] {
-			// Write the unmatched row to the unmatched.csv
+	unmatchedWriter.Write(preferences.Header)
+	for _, record := range preferences.Rows {
+		if !matched[strings.TrimSpace(preferences.Get(record, "full_name", "student_full_name"))] {
 			unmatchedWriter.Write(record)
 		}
 	}
 
 	fmt.Println("Unmatched rows written to unmatched.csv.")
 }
+
+// pad returns the record with empty fields added up to the given number of columns
+func pad(record []string, columns int) []string {
+	for len(record) < columns {
+		record = append(record, "")
+	}
+	return record
+}